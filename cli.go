@@ -1,10 +1,13 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 )
 
 // version is the current version of the CLI.
@@ -13,35 +16,148 @@ var version string
 // Cli represents the command-line interface.
 type Cli struct {
 	OutStream, ErrStream io.Writer
+	InStream             io.Reader
 }
 
 // CliArgs holds the parsed command-line arguments.
 type CliArgs struct {
 	ShowHelp    bool
 	ShowVersion bool
+	DryRun      bool
+	Diff        bool
+	All         bool
+	List        bool
+	Overwrite   OverwritePolicy
+	Remote      string
+	Owner       string
+	Repo        string
+	Online      bool
+	Vars        map[string]string
+	Strict      bool
 	Templates   []string
 }
 
+// varFlag collects repeated key=value flags into a map.
+type varFlag map[string]string
+
+func (v varFlag) String() string {
+	pairs := make([]string, 0, len(v))
+	for key, value := range v {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (v varFlag) Set(value string) error {
+	key, val, found := strings.Cut(value, "=")
+	if !found || key == "" {
+		return fmt.Errorf("invalid variable %q: must be key=value", value)
+	}
+
+	v[key] = val
+
+	return nil
+}
+
 // ParseArgs parses command-line arguments.
 func ParseArgs(args []string) (CliArgs, error) {
-	var cliArgs CliArgs
+	cliArgs := CliArgs{Vars: make(map[string]string)}
 
 	flags := flag.NewFlagSet("gh-dot-tmpl", flag.ContinueOnError)
 	flags.BoolVar(&cliArgs.ShowHelp, "h", false, "Show help message")
 	flags.BoolVar(&cliArgs.ShowHelp, "help", false, "Show help message")
 	flags.BoolVar(&cliArgs.ShowVersion, "v", false, "Show version")
 	flags.BoolVar(&cliArgs.ShowVersion, "version", false, "Show version")
+	flags.BoolVar(&cliArgs.DryRun, "dry-run", false, "Print rendered templates instead of writing them")
+	flags.BoolVar(&cliArgs.Diff, "diff", false, "Print a diff against existing files instead of writing them")
+	flags.BoolVar(&cliArgs.All, "all", false, "Generate all configured templates")
+	flags.BoolVar(&cliArgs.List, "list", false, "List configured templates")
+	flags.StringVar(&cliArgs.Remote, "remote", "", "Git remote to read the owner and repository from")
+	flags.StringVar(&cliArgs.Owner, "owner", "", "Repository owner to use instead of the one in the remote")
+	flags.StringVar(&cliArgs.Repo, "repo", "", "Repository name to use instead of the one in the remote")
+	flags.BoolVar(&cliArgs.Online, "online", false, "Fetch repository metadata from the GitHub API")
+	flags.Var(varFlag(cliArgs.Vars), "var", "Set a template variable as key=value (can be repeated)")
+	flags.BoolVar(&cliArgs.Strict, "strict", false, "Fail on references to missing fields and variables")
+
+	var force, skipExisting, interactive, backup bool
+
+	flags.BoolVar(&force, "force", false, "Overwrite existing files")
+	flags.BoolVar(&skipExisting, "skip-existing", false, "Skip existing files")
+	flags.BoolVar(&interactive, "interactive", false, "Ask before overwriting existing files")
+	flags.BoolVar(&backup, "backup", false, "Back up existing files before overwriting them")
+
+	templates, err := parseInterspersed(flags, args)
+	if err != nil {
+		return cliArgs, err
+	}
 
-	if err := flags.Parse(args); err != nil {
-		// nolint: wrapcheck
+	overwrite, err := overwritePolicyFromFlags(map[OverwritePolicy]bool{
+		OverwriteForce:  force,
+		OverwriteSkip:   skipExisting,
+		OverwritePrompt: interactive,
+		OverwriteBackup: backup,
+	})
+	if err != nil {
 		return cliArgs, err
 	}
 
-	cliArgs.Templates = flags.Args()
+	cliArgs.Overwrite = overwrite
+	cliArgs.Templates = templates
+
+	if cliArgs.All && len(cliArgs.Templates) > 0 {
+		return cliArgs, errors.New("--all cannot be combined with template names")
+	}
 
 	return cliArgs, nil
 }
 
+// parseInterspersed parses flags given before, between and after the positional arguments,
+// which it returns. Arguments following "--" are never parsed as flags.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := flags.Parse(args); err != nil {
+			// nolint: wrapcheck
+			return nil, err
+		}
+
+		rest := flags.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// overwritePolicyFromFlags returns the single overwrite policy selected on the command line.
+func overwritePolicyFromFlags(flags map[OverwritePolicy]bool) (OverwritePolicy, error) {
+	var selected OverwritePolicy
+
+	for policy, set := range flags {
+		if !set {
+			continue
+		}
+
+		if selected != "" {
+			return "", errors.New("only one of --force, --skip-existing, --interactive and --backup may be given")
+		}
+
+		selected = policy
+	}
+
+	return selected, nil
+}
+
 // Run parses command-line arguments and executes the appropriate action.
 func (cli *Cli) Run() int {
 	cliArgs, err := ParseArgs(os.Args[1:])
@@ -60,7 +176,16 @@ func (cli *Cli) Run() int {
 		return 0
 	}
 
-	if len(cliArgs.Templates) == 0 {
+	if cliArgs.List {
+		if err := ListTemplates(cli.OutStream); err != nil {
+			fmt.Fprintf(cli.ErrStream, "Error: %s\n", err)
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(cliArgs.Templates) == 0 && !cliArgs.All {
 		fmt.Fprintf(cli.ErrStream, "Error: No template names provided\n")
 		cli.usage()
 
@@ -69,7 +194,7 @@ func (cli *Cli) Run() int {
 
 	templateName := cliArgs.Templates
 
-	err = Generate(templateName)
+	err = Generate(templateName, cli.generateOptions(cliArgs))
 	if err != nil {
 		fmt.Fprintf(cli.ErrStream, "Error: %s\n", err)
 		return 1
@@ -78,6 +203,27 @@ func (cli *Cli) Run() int {
 	return 0
 }
 
+// generateOptions builds the options for Generate from the parsed arguments.
+func (cli *Cli) generateOptions(cliArgs CliArgs) GenerateOptions {
+	return GenerateOptions{
+		DryRun:      cliArgs.DryRun,
+		Diff:        cliArgs.Diff,
+		All:         cliArgs.All,
+		Color:       isTerminal(cli.OutStream),
+		Overwrite:   cliArgs.Overwrite,
+		Remote:      cliArgs.Remote,
+		Owner:       cliArgs.Owner,
+		Repo:        cliArgs.Repo,
+		Online:      cliArgs.Online,
+		Vars:        cliArgs.Vars,
+		Strict:      cliArgs.Strict,
+		Interactive: isTerminal(cli.InStream),
+		OutStream:   cli.OutStream,
+		ErrStream:   cli.ErrStream,
+		InStream:    cli.InStream,
+	}
+}
+
 // usage prints the help message.
 func (cli *Cli) usage() {
 	fmt.Fprintf(cli.OutStream, `Usage: gh-dot-tmpl [options] [template_name...]
@@ -85,8 +231,22 @@ func (cli *Cli) usage() {
 Options:
   -h, --help       Show help message
   -v, --version    Show version
+  --dry-run        Print rendered templates instead of writing them
+  --diff           Print a diff against existing files instead of writing them
+  --all            Generate all configured templates
+  --list           List configured templates
+  --force          Overwrite existing files
+  --skip-existing  Skip existing files
+  --interactive    Ask before overwriting existing files
+  --backup         Back up existing files before overwriting them
+  --remote <name>  Git remote to read the owner and repository from
+  --owner <owner>  Repository owner to use instead of the one in the remote
+  --repo <repo>    Repository name to use instead of the one in the remote
+  --online         Fetch repository metadata from the GitHub API
+  --var key=value  Set a template variable (can be repeated)
+  --strict         Fail on references to missing fields and variables
 
 Arguments:
-  template_name...  Names of the templates to process
+  template_name...  Names of the templates or template groups to process
 `)
 }
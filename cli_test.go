@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +75,168 @@ func TestParseArgs_Templates(t *testing.T) {
 	}
 }
 
+func TestParseArgs_DryRun(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"--dry-run", "template1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !cliArgs.DryRun {
+		t.Errorf("Expected DryRun true, got false")
+	}
+
+	if len(cliArgs.Templates) != 1 || cliArgs.Templates[0] != "template1" {
+		t.Errorf("Expected templates [template1], got %v", cliArgs.Templates)
+	}
+}
+
+func TestParseArgs_Diff(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"--diff", "template1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !cliArgs.Diff {
+		t.Errorf("Expected Diff true, got false")
+	}
+}
+
+func TestParseArgs_Overwrite(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected OverwritePolicy
+	}{
+		{args: []string{"template1"}, expected: ""},
+		{args: []string{"--force", "template1"}, expected: OverwriteForce},
+		{args: []string{"--skip-existing", "template1"}, expected: OverwriteSkip},
+		{args: []string{"--interactive", "template1"}, expected: OverwritePrompt},
+		{args: []string{"--backup", "template1"}, expected: OverwriteBackup},
+	}
+
+	for _, test := range tests {
+		cliArgs, err := ParseArgs(test.args)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if cliArgs.Overwrite != test.expected {
+			t.Errorf("Expected Overwrite %q, got %q", test.expected, cliArgs.Overwrite)
+		}
+	}
+}
+
+func TestParseArgs_OverwriteConflict(t *testing.T) {
+	_, err := ParseArgs([]string{"--force", "--backup", "template1"})
+	if err == nil {
+		t.Fatalf("Expected error for conflicting overwrite flags, got nil")
+	}
+}
+
+func TestParseArgs_All(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"--all"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !cliArgs.All {
+		t.Errorf("Expected All true, got false")
+	}
+
+	if _, err := ParseArgs([]string{"--all", "template1"}); err == nil {
+		t.Errorf("Expected error when combining --all with template names, got nil")
+	}
+}
+
+func TestParseArgs_Remote(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"--remote", "upstream", "template1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cliArgs.Remote != "upstream" {
+		t.Errorf("Expected Remote %q, got %q", "upstream", cliArgs.Remote)
+	}
+}
+
+func TestParseArgs_FlagsAfterTemplates(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"issue", "--dry-run", "pr", "--remote", "upstream"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !cliArgs.DryRun || cliArgs.Remote != "upstream" {
+		t.Errorf("Expected DryRun and Remote %q, got %v and %q", "upstream", cliArgs.DryRun, cliArgs.Remote)
+	}
+
+	if strings.Join(cliArgs.Templates, ",") != "issue,pr" {
+		t.Errorf("Expected templates [issue pr], got %v", cliArgs.Templates)
+	}
+
+	cliArgs, err = ParseArgs([]string{"--dry-run", "issue", "--", "--diff"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cliArgs.Diff || strings.Join(cliArgs.Templates, ",") != "issue,--diff" {
+		t.Errorf("Expected arguments after -- to be templates, got %v", cliArgs.Templates)
+	}
+}
+
+func TestParseArgs_OwnerRepo(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"--owner", "testorg", "--repo", "testrepo", "template1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cliArgs.Owner != "testorg" || cliArgs.Repo != "testrepo" {
+		t.Errorf("Expected Owner/Repo %q/%q, got %q/%q", "testorg", "testrepo", cliArgs.Owner, cliArgs.Repo)
+	}
+}
+
+func TestParseArgs_Online(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"--online", "template1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !cliArgs.Online {
+		t.Errorf("Expected Online true, got false")
+	}
+}
+
+func TestParseArgs_Strict(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"--strict", "template1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !cliArgs.Strict {
+		t.Errorf("Expected Strict true, got false")
+	}
+}
+
+func TestParseArgs_Vars(t *testing.T) {
+	cliArgs, err := ParseArgs([]string{"--var", "email=security@example.com", "--var", "channel=#team=ops", "template1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]string{"email": "security@example.com", "channel": "#team=ops"}
+	if len(cliArgs.Vars) != len(expected) {
+		t.Fatalf("Expected Vars %v, got %v", expected, cliArgs.Vars)
+	}
+
+	for key, value := range expected {
+		if cliArgs.Vars[key] != value {
+			t.Errorf("Expected Vars[%q] %q, got %q", key, value, cliArgs.Vars[key])
+		}
+	}
+
+	if _, err := ParseArgs([]string{"--var", "novalue", "template1"}); err == nil {
+		t.Errorf("Expected error for variable without value, got nil")
+	}
+}
+
 func TestCli_Run_Help(t *testing.T) {
 	cli := &Cli{}
 
@@ -99,9 +262,23 @@ func TestCli_Run_Help(t *testing.T) {
 Options:
   -h, --help       Show help message
   -v, --version    Show version
+  --dry-run        Print rendered templates instead of writing them
+  --diff           Print a diff against existing files instead of writing them
+  --all            Generate all configured templates
+  --list           List configured templates
+  --force          Overwrite existing files
+  --skip-existing  Skip existing files
+  --interactive    Ask before overwriting existing files
+  --backup         Back up existing files before overwriting them
+  --remote <name>  Git remote to read the owner and repository from
+  --owner <owner>  Repository owner to use instead of the one in the remote
+  --repo <repo>    Repository name to use instead of the one in the remote
+  --online         Fetch repository metadata from the GitHub API
+  --var key=value  Set a template variable (can be repeated)
+  --strict         Fail on references to missing fields and variables
 
 Arguments:
-  template_name...  Names of the templates to process
+  template_name...  Names of the templates or template groups to process
 `
 	if out.String() != expected {
 		t.Errorf("Expected output %q, got %q", expected, out.String())
@@ -166,9 +343,23 @@ func TestCli_Run_NoArgs(t *testing.T) {
 Options:
   -h, --help       Show help message
   -v, --version    Show version
+  --dry-run        Print rendered templates instead of writing them
+  --diff           Print a diff against existing files instead of writing them
+  --all            Generate all configured templates
+  --list           List configured templates
+  --force          Overwrite existing files
+  --skip-existing  Skip existing files
+  --interactive    Ask before overwriting existing files
+  --backup         Back up existing files before overwriting them
+  --remote <name>  Git remote to read the owner and repository from
+  --owner <owner>  Repository owner to use instead of the one in the remote
+  --repo <repo>    Repository name to use instead of the one in the remote
+  --online         Fetch repository metadata from the GitHub API
+  --var key=value  Set a template variable (can be repeated)
+  --strict         Fail on references to missing fields and variables
 
 Arguments:
-  template_name...  Names of the templates to process
+  template_name...  Names of the templates or template groups to process
 `
 	if out.String() != expectedUsage {
 		t.Errorf("Expected output %q, got %q", expectedUsage, out.String())
@@ -200,3 +391,23 @@ func TestCli_Run_InvalidFlag(t *testing.T) {
 		t.Errorf("Expected error output %q, got %q", expectedError, errOut.String())
 	}
 }
+
+func TestCli_GenerateOptions(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	errOut := new(bytes.Buffer)
+	cli := &Cli{OutStream: new(bytes.Buffer), ErrStream: errOut, InStream: devNull}
+
+	opts := cli.generateOptions(CliArgs{})
+	if opts.Interactive {
+		t.Errorf("Expected input from %s not to be interactive", os.DevNull)
+	}
+
+	if opts.ErrStream != errOut {
+		t.Errorf("Expected prompts to be written to the error stream")
+	}
+}
@@ -4,19 +4,105 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config struct represents the configuration file structure.
 type Config struct {
-	Templates map[string]TemplateConfig `yaml:"templates"`
+	Templates    map[string]TemplateConfig `yaml:"templates"`
+	Groups       map[string][]string       `yaml:"groups"`
+	Hosts        []string                  `yaml:"hosts"`
+	Remote       string                    `yaml:"remote"`
+	Vars         map[string]string         `yaml:"vars"`
+	EnvAllowlist []string                  `yaml:"env_allowlist"`
+	Partials     string                    `yaml:"partials"`
+	Strict       bool                      `yaml:"strict"`
 }
 
+// githubHost is the host name of github.com, which is always allowed.
+const githubHost = "github.com"
+
 // TemplateConfig represents the mapping of template files to generated files.
 type TemplateConfig struct {
-	TemplateFile string `yaml:"template_file"`
-	OutputFile   string `yaml:"output_file"`
+	TemplateFile  string            `yaml:"template_file"`
+	OutputFile    string            `yaml:"output_file"`
+	Overwrite     OverwritePolicy   `yaml:"overwrite"`
+	Mode          FileMode          `yaml:"mode"`
+	PreserveMode  bool              `yaml:"preserve_mode"`
+	Vars          map[string]string `yaml:"vars"`
+	Inputs        []TemplateInput   `yaml:"inputs"`
+	Extends       string            `yaml:"extends"`
+	Delims        Delims            `yaml:"delims"`
+	ExtendsDelims Delims            `yaml:"extends_delims"`
+	Copy          bool              `yaml:"copy"`
+}
+
+// TemplateInput declares a template variable that is prompted for when it is not set.
+type TemplateInput struct {
+	Name    string   `yaml:"name"`
+	Prompt  string   `yaml:"prompt"`
+	Default string   `yaml:"default"`
+	Choices []string `yaml:"choices"`
+}
+
+// accepts reports whether value is one of the input's choices, if it has any.
+func (i TemplateInput) accepts(value string) bool {
+	if len(i.Choices) == 0 {
+		return true
+	}
+
+	for _, choice := range i.Choices {
+		if value == choice {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FileMode is a file permission written as an octal string in the configuration file.
+type FileMode os.FileMode
+
+// UnmarshalYAML parses an octal permission such as "0755".
+func (m *FileMode) UnmarshalYAML(value *yaml.Node) error {
+	s := strings.TrimPrefix(strings.TrimPrefix(value.Value, "0o"), "0O")
+
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > uint64(os.ModePerm) {
+		return fmt.Errorf("invalid file mode %q: must be an octal permission such as \"0644\"", value.Value)
+	}
+
+	*m = FileMode(mode)
+
+	return nil
+}
+
+// Delims are the left and right action delimiters of a template. The defaults are used when they are empty.
+type Delims struct {
+	Left, Right string
+}
+
+// delimsCount is the number of delimiters in delims: the left and the right one.
+const delimsCount = 2
+
+// UnmarshalYAML parses a pair of delimiters such as ["[[", "]]"].
+func (d *Delims) UnmarshalYAML(value *yaml.Node) error {
+	var delims []string
+	if err := value.Decode(&delims); err != nil {
+		return fmt.Errorf("invalid delims: %w", err)
+	}
+
+	if len(delims) != delimsCount || delims[0] == "" || delims[1] == "" {
+		return fmt.Errorf("invalid delims %q: must be a left and a right delimiter such as [\"[[\", \"]]\"]", delims)
+	}
+
+	d.Left, d.Right = delims[0], delims[1]
+
+	return nil
 }
 
 // LoadConfig reads the configuration file and unmarshals it into a Config struct.
@@ -37,6 +123,93 @@ func LoadConfig(configPath string) (*Config, error) {
 	return &config, nil
 }
 
+// AllowedHosts returns the GitHub hosts that remotes may point to:
+// github.com, the hosts in the configuration file and the host in GH_HOST.
+func (c *Config) AllowedHosts() []string {
+	hosts := append([]string{githubHost}, c.Hosts...)
+
+	if host := os.Getenv("GH_HOST"); host != "" {
+		hosts = append(hosts, host)
+	}
+
+	return hosts
+}
+
+// TemplateNames returns the names of all configured templates in sorted order.
+func (c *Config) TemplateNames() []string {
+	names := make([]string, 0, len(c.Templates))
+	for name := range c.Templates {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ExpandTemplateNames replaces group names with the templates they contain.
+// Template names take precedence over group names, and each template is returned only once.
+func (c *Config) ExpandTemplateNames(names []string) []string {
+	var expanded []string
+
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+
+			expanded = append(expanded, name)
+		}
+	}
+
+	for _, name := range names {
+		group, isGroup := c.Groups[name]
+		if _, isTemplate := c.Templates[name]; isTemplate || !isGroup {
+			add(name)
+			continue
+		}
+
+		for _, member := range group {
+			add(member)
+		}
+	}
+
+	return expanded
+}
+
+// ValidateTemplateNames checks that every name refers to a configured template.
+// All unknown names are reported at once, together with the closest configured names.
+func (c *Config) ValidateTemplateNames(names []string) error {
+	candidates := c.TemplateNames()
+	for group := range c.Groups {
+		candidates = append(candidates, group)
+	}
+
+	var unknown []string
+
+	for _, name := range names {
+		if _, ok := c.Templates[name]; ok {
+			continue
+		}
+
+		description := strconv.Quote(name)
+		if suggestions := SuggestNames(name, candidates); len(suggestions) > 0 {
+			description += fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, ", "))
+		}
+
+		unknown = append(unknown, description)
+	}
+
+	switch len(unknown) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("unknown template %s", unknown[0])
+	default:
+		return fmt.Errorf("unknown templates %s", strings.Join(unknown, ", "))
+	}
+}
+
 // GetConfigPath returns the path to the configuration file.
 func GetConfigPath() string {
 	configDir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "gh-dot-tmpl")
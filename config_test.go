@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -54,6 +55,198 @@ templates:
 	}
 }
 
+func TestLoadConfigFileMode(t *testing.T) {
+	configContent := `
+templates:
+  quoted:
+    template_file: "hook.tpl"
+    output_file: "hook.sh"
+    mode: "0755"
+  plain:
+    template_file: "workflow.tpl"
+    output_file: "workflow.yml"
+    mode: 0640
+  prefixed:
+    template_file: "script.tpl"
+    output_file: "script.sh"
+    mode: 0o700
+`
+	filePath, cleanup := createTempConfigFile(t, configContent)
+
+	defer cleanup()
+
+	config, err := LoadConfig(filePath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	expected := map[string]FileMode{"quoted": 0o755, "plain": 0o640, "prefixed": 0o700}
+	for name, mode := range expected {
+		if config.Templates[name].Mode != mode {
+			t.Errorf("Expected %s mode to be %o, got %o", name, mode, config.Templates[name].Mode)
+		}
+	}
+}
+
+func TestLoadConfigInvalidFileMode(t *testing.T) {
+	for _, mode := range []string{"rwxr-xr-x", "0999", "17777"} {
+		configContent := `
+templates:
+  template1:
+    template_file: "template1.tpl"
+    output_file: "output1.txt"
+    mode: "` + mode + `"
+`
+		filePath, cleanup := createTempConfigFile(t, configContent)
+
+		_, err := LoadConfig(filePath)
+		if err == nil {
+			t.Errorf("Expected error for invalid mode %q, got nil", mode)
+		}
+
+		cleanup()
+	}
+}
+
+func TestLoadConfigDelims(t *testing.T) {
+	configContent := `
+templates:
+  workflow:
+    template_file: "workflow.tpl"
+    output_file: "workflow.yml"
+    delims: ["[[", "]]"]
+    extends_delims: ["<%", "%>"]
+`
+	filePath, cleanup := createTempConfigFile(t, configContent)
+
+	defer cleanup()
+
+	config, err := LoadConfig(filePath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if delims := config.Templates["workflow"].Delims; delims != (Delims{"[[", "]]"}) {
+		t.Errorf("Expected delims [[ and ]], got %v", delims)
+	}
+
+	if delims := config.Templates["workflow"].ExtendsDelims; delims != (Delims{"<%", "%>"}) {
+		t.Errorf("Expected extends_delims <%% and %%>, got %v", delims)
+	}
+}
+
+func TestLoadConfigInvalidDelims(t *testing.T) {
+	for _, delims := range []string{`"[["`, `["[["]`, `["[[", ""]`, `["[[", "]]", "]]"]`} {
+		configContent := `
+templates:
+  workflow:
+    template_file: "workflow.tpl"
+    output_file: "workflow.yml"
+    delims: ` + delims + `
+`
+		filePath, cleanup := createTempConfigFile(t, configContent)
+
+		_, err := LoadConfig(filePath)
+		if err == nil {
+			t.Errorf("Expected error for invalid delims %s, got nil", delims)
+		}
+
+		cleanup()
+	}
+}
+
+func TestLoadConfigGroups(t *testing.T) {
+	configContent := `
+templates:
+  issue:
+    template_file: "issue.tpl"
+    output_file: "issue.md"
+  pr:
+    template_file: "pr.tpl"
+    output_file: "pr.md"
+groups:
+  oss-default:
+    - issue
+    - pr
+`
+	filePath, cleanup := createTempConfigFile(t, configContent)
+
+	defer cleanup()
+
+	config, err := LoadConfig(filePath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	group := config.Groups["oss-default"]
+	if len(group) != 2 || group[0] != "issue" || group[1] != "pr" {
+		t.Errorf("Expected group oss-default to be [issue pr], got %v", group)
+	}
+}
+
+func TestAllowedHosts(t *testing.T) {
+	config := &Config{Hosts: []string{"ghe.corp.example"}}
+
+	t.Setenv("GH_HOST", "")
+
+	if got := strings.Join(config.AllowedHosts(), ","); got != "github.com,ghe.corp.example" {
+		t.Errorf("Expected allowed hosts [github.com ghe.corp.example], got %v", got)
+	}
+
+	t.Setenv("GH_HOST", "ghe.other.example")
+
+	if got := strings.Join(config.AllowedHosts(), ","); got != "github.com,ghe.corp.example,ghe.other.example" {
+		t.Errorf("Expected GH_HOST to be allowed, got %v", got)
+	}
+}
+
+func TestTemplateNames(t *testing.T) {
+	config := &Config{
+		Templates: map[string]TemplateConfig{
+			"pr":       {},
+			"issue":    {},
+			"security": {},
+		},
+	}
+
+	got := config.TemplateNames()
+	if strings.Join(got, ",") != "issue,pr,security" {
+		t.Errorf("Expected sorted template names [issue pr security], got %v", got)
+	}
+}
+
+func TestExpandTemplateNames(t *testing.T) {
+	config := &Config{
+		Templates: map[string]TemplateConfig{
+			"issue":    {},
+			"pr":       {},
+			"security": {},
+		},
+		Groups: map[string][]string{
+			"oss-default": {"issue", "pr"},
+			"security":    {"issue"},
+		},
+	}
+
+	testCases := []struct {
+		names    []string
+		expected []string
+	}{
+		{[]string{"issue"}, []string{"issue"}},
+		{[]string{"oss-default"}, []string{"issue", "pr"}},
+		{[]string{"pr", "oss-default"}, []string{"pr", "issue"}},
+		{[]string{"security"}, []string{"security"}},
+		{[]string{"unknown"}, []string{"unknown"}},
+	}
+
+	for _, tc := range testCases {
+		got := config.ExpandTemplateNames(tc.names)
+		if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("Expected %v to expand to %v, got %v", tc.names, tc.expected, got)
+		}
+	}
+}
+
 func TestLoadConfigNotExist(t *testing.T) {
 	_, err := LoadConfig("nonexistent.yaml")
 	if err == nil {
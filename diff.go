@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Number of unchanged lines shown around each change in a unified diff.
+const diffContextLines = 3
+
+// ANSI escape sequences used to colour diff output.
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+// diffOp is a single line of an edit script.
+type diffOp struct {
+	kind byte // ' ' for unchanged, '-' for deleted, '+' for inserted
+	line string
+}
+
+// maxDiffCells bounds the size of the table used to diff the lines that differ between two contents.
+// Larger contents are only reported as differing.
+const maxDiffCells = 1 << 24
+
+// binarySniffLength is how many leading bytes are searched for a NUL byte to detect binary content, as git does.
+const binarySniffLength = 8000
+
+// UnifiedDiff returns a unified diff that turns oldContent into newContent.
+// An empty string is returned when the contents are identical.
+// Binary contents, and contents too large to compare line by line, are only reported as differing.
+func UnifiedDiff(oldName, newName string, oldContent, newContent []byte) string {
+	if isBinary(oldContent) || isBinary(newContent) {
+		if bytes.Equal(oldContent, newContent) {
+			return ""
+		}
+
+		return fmt.Sprintf("Binary files %s and %s differ\n", oldName, newName)
+	}
+
+	ops, ok := diffLines(splitLines(string(oldContent)), splitLines(string(newContent)))
+	if !ok {
+		return fmt.Sprintf("Files %s and %s differ\n", oldName, newName)
+	}
+
+	hunks := formatHunks(ops)
+	if hunks == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("--- %s\n+++ %s\n%s", oldName, newName, hunks)
+}
+
+// splitLines splits s into lines, keeping the trailing newline of each line.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines computes an edit script from a to b based on their longest common subsequence.
+// It returns false when the lines between the common prefix and suffix are too many to compare.
+func diffLines(a, b []string) ([]diffOp, bool) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA) > 0 && len(midB) > maxDiffCells/len(midA) {
+		return nil, false
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	ops = append(ops, diffMiddle(midA, midB)...)
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	return ops, true
+}
+
+// diffMiddle computes an edit script from a to b with a full longest common subsequence table.
+func diffMiddle(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops
+}
+
+// formatHunks groups the edit script into hunks with surrounding context and formats them.
+func formatHunks(ops []diffOp) string {
+	// oldLine and newLine hold the number of lines consumed before each op.
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+
+	for k, op := range ops {
+		oldLine[k+1], newLine[k+1] = oldLine[k], newLine[k]
+		if op.kind != '+' {
+			oldLine[k+1]++
+		}
+
+		if op.kind != '-' {
+			newLine[k+1]++
+		}
+	}
+
+	var out strings.Builder
+
+	for i, prevStop := 0, 0; i < len(ops); prevStop = i {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+
+		if i == len(ops) {
+			break
+		}
+
+		start := max(i-diffContextLines, prevStop)
+		end := i
+
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j
+			} else if j-end > 2*diffContextLines {
+				break
+			}
+		}
+
+		stop := min(end+diffContextLines+1, len(ops))
+
+		writeHunk(&out, ops[start:stop], oldLine[start], newLine[start], oldLine[stop], newLine[stop])
+
+		i = stop
+	}
+
+	return out.String()
+}
+
+// writeHunk writes a single hunk, including its header, to out.
+func writeHunk(out *strings.Builder, ops []diffOp, oldStart, newStart, oldEnd, newEnd int) {
+	oldCount, newCount := oldEnd-oldStart, newEnd-newStart
+
+	if oldCount > 0 {
+		oldStart++
+	}
+
+	if newCount > 0 {
+		newStart++
+	}
+
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+	for _, op := range ops {
+		out.WriteByte(op.kind)
+		out.WriteString(op.line)
+
+		if !strings.HasSuffix(op.line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// colorizeDiff wraps the lines of a unified diff in ANSI colour sequences.
+func colorizeDiff(diff string) string {
+	var out strings.Builder
+
+	inHeader := true
+
+	for _, line := range splitLines(diff) {
+		text := strings.TrimSuffix(line, "\n")
+
+		switch {
+		case strings.HasPrefix(text, "@@"):
+			inHeader = false
+
+			out.WriteString(colorCyan + text + colorReset)
+		case inHeader:
+			out.WriteString(colorBold + text + colorReset)
+		case strings.HasPrefix(text, "-"):
+			out.WriteString(colorRed + text + colorReset)
+		case strings.HasPrefix(text, "+"):
+			out.WriteString(colorGreen + text + colorReset)
+		default:
+			out.WriteString(text)
+		}
+
+		out.WriteString("\n")
+	}
+
+	return out.String()
+}
+
+// isBinary reports whether content looks binary, that is whether it has a NUL byte near its start.
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binarySniffLength)], 0) >= 0
+}
+
+// isTerminal reports whether stream is a terminal.
+// A character device such as /dev/null is not a terminal, so it is checked with isatty rather than the file mode.
+func isTerminal(stream any) bool {
+	f, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+
+	// nolint: gosec
+	return term.IsTerminal(int(f.Fd()))
+}
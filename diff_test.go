@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "identical",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			name:     "new file",
+			old:      "",
+			new:      "a\nb\n",
+			expected: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:     "changed line",
+			old:      "a\nb\nc\n",
+			new:      "a\nB\nc\n",
+			expected: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name:     "no newline at end of file",
+			old:      "a\n",
+			new:      "a",
+			expected: "--- old\n+++ new\n@@ -1,1 +1,1 @@\n-a\n+a\n\\ No newline at end of file\n",
+		},
+		{
+			name:     "identical binary",
+			old:      "\x89PNG\x00\x01",
+			new:      "\x89PNG\x00\x01",
+			expected: "",
+		},
+		{
+			name:     "changed binary",
+			old:      "",
+			new:      "\x89PNG\x00\x01",
+			expected: "Binary files old and new differ\n",
+		},
+		{
+			name:     "large file with one change",
+			old:      strings.Repeat("a\n", 10000) + "b\n" + strings.Repeat("a\n", 10000),
+			new:      strings.Repeat("a\n", 10000) + "B\n" + strings.Repeat("a\n", 10000),
+			expected: "--- old\n+++ new\n@@ -9998,7 +9998,7 @@\n a\n a\n a\n-b\n+B\n a\n a\n a\n",
+		},
+		{
+			name:     "too many changed lines",
+			old:      strings.Repeat("a\n", 5000),
+			new:      strings.Repeat("b\n", 5000),
+			expected: "Files old and new differ\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := UnifiedDiff("old", "new", []byte(tc.old), []byte(tc.new))
+			if got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestColorizeDiff(t *testing.T) {
+	diff := "--- old\n+++ new\n@@ -1,1 +1,1 @@\n-a\n+b\n"
+
+	got := colorizeDiff(diff)
+
+	for _, expected := range []string{
+		colorBold + "--- old" + colorReset,
+		colorCyan + "@@ -1,1 +1,1 @@" + colorReset,
+		colorRed + "-a" + colorReset,
+		colorGreen + "+b" + colorReset,
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Expected colorized diff to contain %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	if isTerminal(new(bytes.Buffer)) {
+		t.Errorf("Expected buffer not to be a terminal")
+	}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	if isTerminal(devNull) {
+		t.Errorf("Expected %s not to be a terminal", os.DevNull)
+	}
+}
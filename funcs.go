@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// templateFuncs returns the functions available to templates.
+// Their argument order follows Sprig, so the value being transformed comes last and can be piped in.
+func templateFuncs(opts RenderOptions) template.FuncMap {
+	return template.FuncMap{
+		// Strings
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, replacement, s string) string { return strings.ReplaceAll(s, old, replacement) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"quote":      func(s string) string { return fmt.Sprintf("%q", s) },
+		"squote":     func(s string) string { return "'" + s + "'" },
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		// Lists
+		"list":  func(items ...any) []any { return items },
+		"split": func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":  join,
+		"first": first,
+		"last":  last,
+		"has":   has,
+		// Dates
+		"now":  func() time.Time { return now() },
+		"date": func(layout string, t time.Time) string { return t.Format(layout) },
+		// Defaults
+		"default":  func(def, value any) any { return defaultValue(def, value) },
+		"empty":    isEmpty,
+		"required": required,
+		// Environment
+		"env": envFunc(opts.EnvAllowlist),
+	}
+}
+
+// title upper-cases the first letter of each word in s.
+func title(s string) string {
+	runes := []rune(s)
+
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '-' || runes[i-1] == '_' {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+
+	return string(runes)
+}
+
+// indent prefixes every line of s with the given number of spaces.
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// listItems returns the elements of a slice or array as a []any.
+func listItems(list any) ([]any, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a list, got %T", list)
+	}
+
+	items := make([]any, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+
+	return items, nil
+}
+
+// join concatenates the elements of list, separated by sep.
+func join(sep string, list any) (string, error) {
+	items, err := listItems(list)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(item)
+	}
+
+	return strings.Join(parts, sep), nil
+}
+
+// first returns the first element of list, or nil when it is empty.
+func first(list any) (any, error) {
+	items, err := listItems(list)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	return items[0], nil
+}
+
+// last returns the last element of list, or nil when it is empty.
+func last(list any) (any, error) {
+	items, err := listItems(list)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	return items[len(items)-1], nil
+}
+
+// has reports whether list contains needle.
+func has(needle, list any) (bool, error) {
+	items, err := listItems(list)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(items, func(item any) bool { return reflect.DeepEqual(item, needle) }), nil
+}
+
+// isEmpty reports whether value is nil or the zero value of its type, or an empty list or map.
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// defaultValue returns value, or def when value is empty.
+func defaultValue(def, value any) any {
+	if isEmpty(value) {
+		return def
+	}
+
+	return value
+}
+
+// required returns value, or an error with the given message when value is empty.
+func required(message string, value any) (any, error) {
+	if isEmpty(value) {
+		return nil, errors.New(message)
+	}
+
+	return value, nil
+}
+
+// envFunc returns the env template function, which reads an environment variable and
+// returns the optional default when it is unset or empty.
+// When allowlist is not nil, reading a variable outside of it is an error.
+func envFunc(allowlist []string) func(string, ...string) (string, error) {
+	return func(name string, defaults ...string) (string, error) {
+		if allowlist != nil && !slices.Contains(allowlist, name) {
+			return "", fmt.Errorf("environment variable %q is not in env_allowlist", name)
+		}
+
+		if value := os.Getenv(name); value != "" {
+			return value, nil
+		}
+
+		if len(defaults) > 0 {
+			return defaults[0], nil
+		}
+
+		return "", nil
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	originalNow := now
+	defer func() { now = originalNow }()
+
+	now = func() time.Time { return time.Date(2024, 7, 1, 12, 30, 0, 0, time.UTC) }
+
+	data := map[string]any{
+		"Repository": "Gh-Dot-Tmpl",
+		"Topics":     []string{"go", "cli"},
+		"Empty":      "",
+		"Vars":       map[string]string{"team": "ops"},
+	}
+
+	testCases := []struct {
+		template string
+		expected string
+	}{
+		{`{{ .Repository | lower }}`, "gh-dot-tmpl"},
+		{`{{ .Repository | upper }}`, "GH-DOT-TMPL"},
+		{`{{ "hello wide world" | title }}`, "Hello Wide World"},
+		{`{{ "  padded  " | trim }}`, "padded"},
+		{`{{ "v1.2.3" | trimPrefix "v" }}`, "1.2.3"},
+		{`{{ "repo.git" | trimSuffix ".git" }}`, "repo"},
+		{`{{ .Repository | replace "-" "_" }}`, "Gh_Dot_Tmpl"},
+		{`{{ if contains "Dot" .Repository }}yes{{ end }}`, "yes"},
+		{`{{ if hasPrefix "Gh" .Repository }}yes{{ end }}`, "yes"},
+		{`{{ if hasSuffix "Tmpl" .Repository }}yes{{ end }}`, "yes"},
+		{`{{ "=" | repeat 3 }}`, "==="},
+		{`{{ "a\"b" | quote }} {{ "c" | squote }}`, `"a\"b" 'c'`},
+		{`{{ .Topics | join ", " }}`, "go, cli"},
+		{`{{ list 1 "two" 3 | join "-" }}`, "1-two-3"},
+		{`{{ "a,b,c" | split "," | last }}`, "c"},
+		{`{{ .Topics | first }}`, "go"},
+		{`{{ if has "cli" .Topics }}yes{{ end }}`, "yes"},
+		{`{{ now | date "2006-01-02" }}`, "2024-07-01"},
+		{`{{ .Empty | default "fallback" }}`, "fallback"},
+		{`{{ .Vars.team | default "fallback" }}`, "ops"},
+		{`{{ .Vars.missing | default "fallback" }}`, "fallback"},
+		{`{{ if empty .Empty }}empty{{ end }}`, "empty"},
+		{`{{ .Repository | required "repository is required" }}`, "Gh-Dot-Tmpl"},
+		{`key:{{ "a: 1\nb: 2" | nindent 2 }}`, "key:\n  a: 1\n  b: 2"},
+		{`{{ "a\nb" | indent 4 }}`, "    a\n    b"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.template, func(t *testing.T) {
+			tmpl, err := template.New("test").Funcs(templateFuncs(RenderOptions{})).Parse(tc.template)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+
+			var buf strings.Builder
+			if err := tmpl.Execute(&buf, data); err != nil {
+				t.Fatalf("failed to execute template: %v", err)
+			}
+
+			if buf.String() != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestTemplateFuncs_Required(t *testing.T) {
+	tmpl, err := template.New("test").Funcs(templateFuncs(RenderOptions{})).Parse(`{{ .Empty | required "email is required" }}`)
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	err = tmpl.Execute(new(strings.Builder), map[string]string{"Empty": ""})
+	if err == nil || !strings.Contains(err.Error(), "email is required") {
+		t.Errorf("Expected required error, got %v", err)
+	}
+}
+
+func TestEnvFunc(t *testing.T) {
+	t.Setenv("ORG_SECURITY_EMAIL", "security@example.com")
+	t.Setenv("EMPTY_VAR", "")
+
+	testCases := []struct {
+		name      string
+		allowlist []string
+		variable  string
+		defaults  []string
+		expected  string
+		wantErr   bool
+	}{
+		{name: "set", variable: "ORG_SECURITY_EMAIL", expected: "security@example.com"},
+		{name: "unset", variable: "UNSET_VAR_FOR_TEST", expected: ""},
+		{name: "default", variable: "UNSET_VAR_FOR_TEST", defaults: []string{"fallback"}, expected: "fallback"},
+		{name: "empty uses default", variable: "EMPTY_VAR", defaults: []string{"fallback"}, expected: "fallback"},
+		{name: "allowed", allowlist: []string{"ORG_SECURITY_EMAIL"}, variable: "ORG_SECURITY_EMAIL", expected: "security@example.com"},
+		{name: "not allowed", allowlist: []string{"ORG_SECURITY_EMAIL"}, variable: "HOME", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := envFunc(tc.allowlist)(tc.variable, tc.defaults...)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -1,11 +1,55 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 )
 
-func Generate(templates []string) error {
+// GenerateOptions holds the options that control how templates are generated.
+type GenerateOptions struct {
+	// DryRun prints the rendered templates to OutStream instead of writing them.
+	DryRun bool
+	// Diff prints a unified diff against the existing output files instead of writing them.
+	Diff bool
+	// All generates every configured template instead of the given ones.
+	All bool
+	// Color enables ANSI colours in the diff output.
+	Color bool
+	// Overwrite overrides the overwrite policy configured for each template.
+	Overwrite OverwritePolicy
+	// Owner and Repo override the owner and repository read from the git remote.
+	Owner string
+	Repo  string
+	// Remote is the git remote to read the owner and repository from, overriding the config file.
+	Remote string
+	// Online fetches repository metadata from the GitHub API.
+	Online bool
+	// Vars are template variables that override those in the config file.
+	Vars map[string]string
+	// Strict fails on references to missing fields and variables. It is also enabled by strict in the config file.
+	Strict bool
+	// Interactive reports whether InStream is a terminal that can answer prompts.
+	Interactive bool
+	OutStream   io.Writer
+	// ErrStream receives prompts, so they are not mixed into the output of --dry-run and --diff.
+	ErrStream io.Writer
+	InStream  io.Reader
+}
+
+func Generate(templates []string, opts GenerateOptions) error {
+	if opts.OutStream == nil {
+		opts.OutStream = io.Discard
+	}
+
+	if opts.ErrStream == nil {
+		opts.ErrStream = io.Discard
+	}
+
 	if !IsGitRepository() {
 		return fmt.Errorf("not a git repository")
 	}
@@ -19,20 +63,41 @@ func Generate(templates []string) error {
 		return fmt.Errorf("failed to change directory to git root: %w", err)
 	}
 
-	user, repo, err := GetGithubUserRepo()
+	configPath := GetConfigPath()
+
+	config, err := LoadConfig(configPath)
 	if err != nil {
 		return err
 	}
 
-	configPath := GetConfigPath()
+	if opts.All {
+		templates = config.TemplateNames()
+	}
 
-	config, err := LoadConfig(configPath)
+	// Unknown names are reported before the repository is inspected, which may fail for unrelated reasons.
+	templates = config.ExpandTemplateNames(templates)
+	if err := config.ValidateTemplateNames(templates); err != nil {
+		return err
+	}
+
+	data, err := buildTemplateData(config, gitRoot, opts)
 	if err != nil {
 		return err
 	}
 
+	templateVars, err := resolveTemplateVars(config, templates, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := checkConflicts(config, templates, opts); err != nil {
+		return err
+	}
+
 	for _, template := range templates {
-		if err := processTemplate(config, template, user, repo); err != nil {
+		data.Vars = templateVars[template]
+
+		if err := processTemplate(config, template, data, opts); err != nil {
 			return err
 		}
 	}
@@ -40,16 +105,307 @@ func Generate(templates []string) error {
 	return nil
 }
 
-func processTemplate(config *Config, template, user, repo string) error {
+// resolveTemplateVars returns the variables of each template, asking for missing inputs
+// before any file is written. An answer typed at a prompt is reused by later templates declaring the same input.
+func resolveTemplateVars(config *Config, templates []string, opts GenerateOptions) (map[string]map[string]string, error) {
+	templateVars := make(map[string]map[string]string, len(templates))
+	answers := make(map[string]string)
+
+	for _, template := range templates {
+		templateConfig := config.Templates[template]
+
+		// Answers only apply to the inputs the template declares, and its own vars take precedence.
+		shared := make(map[string]string)
+
+		for _, input := range templateConfig.Inputs {
+			if answer, ok := answers[input.Name]; ok {
+				shared[input.Name] = answer
+			}
+		}
+
+		vars := MergeVars(config.Vars, shared, templateConfig.Vars, opts.Vars)
+
+		var prompted []string
+
+		for _, input := range templateConfig.Inputs {
+			if _, ok := vars[input.Name]; !ok && opts.Interactive {
+				prompted = append(prompted, input.Name)
+			}
+		}
+
+		vars, err := ResolveInputs(template, templateConfig.Inputs, vars, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range prompted {
+			answers[name] = vars[name]
+		}
+
+		templateVars[template] = vars
+	}
+
+	return templateVars, nil
+}
+
+// buildTemplateData gathers the repository metadata exposed to templates.
+func buildTemplateData(config *Config, gitRoot string, opts GenerateOptions) (TemplateData, error) {
+	remoteName := opts.Remote
+	if remoteName == "" {
+		remoteName = config.Remote
+	}
+
+	remote, err := resolveRepository(config, remoteName, gitRoot, opts)
+	if err != nil {
+		return TemplateData{}, err
+	}
+
+	info := GetRepositoryInfo(gitRoot, remoteName)
+
+	var metadata RepoMetadata
+
+	if opts.Online {
+		metadata, err = newGithubClient(remote.Host).GetRepoMetadata(context.Background(), remote.Owner, remote.Repo)
+		if err != nil {
+			return TemplateData{}, err
+		}
+	}
+
+	return TemplateData{
+		Username:      remote.Owner,
+		Repository:    remote.Repo,
+		Host:          remote.Host,
+		RemoteURL:     info.RemoteURL,
+		DefaultBranch: info.DefaultBranch,
+		CurrentBranch: info.CurrentBranch,
+		RootName:      info.RootName,
+		GitUserName:   info.UserName,
+		GitUserEmail:  info.UserEmail,
+		Repo:          metadata,
+	}, nil
+}
+
+// resolveRepository returns the host, owner and repository to expose to templates.
+// They are read from the git remote and overridden by opts.
+func resolveRepository(config *Config, remoteName, gitRoot string, opts GenerateOptions) (RemoteURL, error) {
+	remote, err := GetGithubUserRepo(remoteName, config.AllowedHosts())
+	if err != nil {
+		remote, err = fallbackRepository(err, remoteName, gitRoot, opts)
+		if err != nil {
+			return RemoteURL{}, err
+		}
+	}
+
+	if opts.Owner != "" {
+		remote.Owner = opts.Owner
+	}
+
+	if opts.Repo != "" {
+		remote.Repo = opts.Repo
+	}
+
+	if remote.Owner == "" {
+		return RemoteURL{}, errors.New("unable to determine the repository owner: use --owner or set github.user in git config")
+	}
+
+	return remote, nil
+}
+
+// fallbackRepository returns the repository to use when the git remote cannot be read.
+// In a repository without a remote, the owner comes from git config and the repository
+// from the directory name; an owner guessed from user.name is refused with --online. Otherwise the remote error is returned unless opts overrides both.
+func fallbackRepository(remoteErr error, remoteName, gitRoot string, opts GenerateOptions) (RemoteURL, error) {
+	host := os.Getenv("GH_HOST")
+	if host == "" {
+		host = githubHost
+	}
+
+	var noRemote *NoRemoteError
+	if errors.As(remoteErr, &noRemote) && remoteName == "" {
+		owner := GetGitConfig("github.user")
+		if owner == "" {
+			owner = GetGitConfig("user.name")
+
+			// A display name is rarely an account name, so it is not sent to the GitHub API.
+			if owner != "" && opts.Online && opts.Owner == "" {
+				return RemoteURL{}, fmt.Errorf(
+					"the repository owner %q was guessed from user.name: use --owner to set it with --online", owner)
+			}
+		}
+
+		return RemoteURL{Host: host, Owner: owner, Repo: filepath.Base(gitRoot)}, nil
+	}
+
+	if opts.Owner != "" && opts.Repo != "" {
+		return RemoteURL{Host: host}, nil
+	}
+
+	return RemoteURL{}, remoteErr
+}
+
+func processTemplate(config *Config, template string, data TemplateData, opts GenerateOptions) error {
 	tempPath, err := GetTemplatePath(config, template)
 	if err != nil {
 		return err
 	}
 
-	outputFile := config.Templates[template].OutputFile
-	if err := GenerateFileFromTemplate(tempPath, outputFile, user, repo); err != nil {
+	partials, err := ListPartialFiles(config.Partials)
+	if err != nil {
 		return err
 	}
 
+	templateConfig := config.Templates[template]
+	renderOpts := RenderOptions{
+		EnvAllowlist: config.EnvAllowlist,
+		Partials:     partials,
+		Delims:       templateConfig.Delims,
+		BaseDelims:   templateConfig.ExtendsDelims,
+		Strict:       opts.Strict || config.Strict,
+	}
+
+	if templateConfig.Extends != "" {
+		renderOpts.Base, err = ExpandTilde(templateConfig.Extends)
+		if err != nil {
+			return err
+		}
+	}
+
+	files, err := ListTemplateFiles(tempPath, templateConfig.OutputFile)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := processFile(templateConfig, file, data, renderOpts, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processFile generates, prints or diffs a single output file of a template.
+func processFile(
+	templateConfig TemplateConfig, file TemplateFile, data TemplateData, renderOpts RenderOptions, opts GenerateOptions,
+) error {
+	verbatim, err := IsVerbatim(templateConfig, file.TemplatePath)
+	if err != nil {
+		return err
+	}
+
+	if opts.Diff || opts.DryRun {
+		content, err := fileContent(file.TemplatePath, data, renderOpts, verbatim)
+		if err != nil {
+			return err
+		}
+
+		if opts.Diff {
+			return printTemplateDiff(opts.OutStream, file.OutputPath, content, opts.Color)
+		}
+
+		return printRenderedTemplate(opts.OutStream, file.OutputPath, content)
+	}
+
+	policy := resolveOverwritePolicy(opts.Overwrite, templateConfig.Overwrite)
+
+	write, err := checkOverwrite(file.OutputPath, policy, opts)
+	if err != nil || !write {
+		return err
+	}
+
+	createdDirs, err := CreateParentDirs(file.OutputPath)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range createdDirs {
+		fmt.Fprintf(opts.OutStream, "Created directory %s\n", dir)
+	}
+
+	if verbatim {
+		return copyTemplateFile(templateConfig, file)
+	}
+
+	mode, err := GetFileMode(templateConfig, file.OutputPath, defaultFileMode)
+	if err != nil {
+		return err
+	}
+
+	return GenerateFileFromTemplate(file.TemplatePath, file.OutputPath, data, mode, renderOpts)
+}
+
+// fileContent returns the content of the output file: the template file itself when it is
+// copied verbatim, and the rendered template otherwise.
+func fileContent(templatePath string, data TemplateData, renderOpts RenderOptions, verbatim bool) ([]byte, error) {
+	if !verbatim {
+		return RenderTemplate(templatePath, data, renderOpts)
+	}
+
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	return content, nil
+}
+
+// copyTemplateFile copies a template file verbatim, keeping its permission unless the template sets or preserves another.
+func copyTemplateFile(templateConfig TemplateConfig, file TemplateFile) error {
+	info, err := os.Stat(file.TemplatePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat template file: %w", err)
+	}
+
+	mode, err := GetFileMode(templateConfig, file.OutputPath, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	return CopyFile(file.TemplatePath, file.OutputPath, mode)
+}
+
+// printRenderedTemplate writes the rendered template to w, preceded by a header naming the output file.
+// Binary content is only described by its size.
+func printRenderedTemplate(w io.Writer, outputPath string, content []byte) error {
+	fmt.Fprintf(w, "==> %s <==\n", outputPath)
+
+	if isBinary(content) {
+		fmt.Fprintf(w, "Binary file (%d bytes)\n", len(content))
+		return nil
+	}
+
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("failed to write rendered template: %w", err)
+	}
+
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+// printTemplateDiff writes a unified diff between the existing output file and the rendered template to w.
+func printTemplateDiff(w io.Writer, outputPath string, content []byte, color bool) error {
+	oldName := outputPath
+
+	current, err := os.ReadFile(outputPath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read output file: %w", err)
+		}
+
+		oldName = os.DevNull
+	}
+
+	diff := UnifiedDiff(oldName, outputPath, current, content)
+	if color {
+		diff = colorizeDiff(diff)
+	}
+
+	if _, err := io.WriteString(w, diff); err != nil {
+		return fmt.Errorf("failed to write diff: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// githubAPITimeout bounds the time spent fetching repository metadata.
+const githubAPITimeout = 10 * time.Second
+
+// RepoMetadata holds repository metadata fetched from the GitHub API.
+type RepoMetadata struct {
+	Description   string
+	Homepage      string
+	License       string
+	LicenseName   string
+	Topics        []string
+	Visibility    string
+	DefaultBranch string
+}
+
+// GithubClient fetches repository metadata from the GitHub REST API.
+type GithubClient struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// newGithubClient is a variable for the client constructor, so it can be mocked in tests.
+var newGithubClient = NewGithubClient
+
+// NewGithubClient returns a client for the API of the given GitHub host,
+// authenticated with the same token gh uses.
+func NewGithubClient(host string) *GithubClient {
+	baseURL := "https://api.github.com"
+	if host != githubHost {
+		baseURL = "https://" + host + "/api/v3"
+	}
+
+	return &GithubClient{
+		BaseURL:    baseURL,
+		Token:      githubToken(host),
+		HTTPClient: &http.Client{Timeout: githubAPITimeout},
+	}
+}
+
+// githubToken returns the token for host from the environment, falling back to gh auth token.
+func githubToken(host string) string {
+	envVars := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if host != githubHost {
+		envVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+
+	for _, envVar := range envVars {
+		if token := os.Getenv(envVar); token != "" {
+			return token
+		}
+	}
+
+	output, err := exec.Command("gh", "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+// repoResponse is the subset of the GitHub repository API response used by templates.
+type repoResponse struct {
+	Description   string   `json:"description"`
+	Homepage      string   `json:"homepage"`
+	Topics        []string `json:"topics"`
+	Visibility    string   `json:"visibility"`
+	Private       bool     `json:"private"`
+	DefaultBranch string   `json:"default_branch"`
+	License       *struct {
+		SPDXID string `json:"spdx_id"`
+		Name   string `json:"name"`
+	} `json:"license"`
+}
+
+// GetRepoMetadata fetches the metadata of owner/repo.
+func (c *GithubClient) GetRepoMetadata(ctx context.Context, owner, repo string) (RepoMetadata, error) {
+	endpoint := c.BaseURL + "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return RepoMetadata{}, fmt.Errorf("failed to create GitHub API request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return RepoMetadata{}, fmt.Errorf("failed to query GitHub API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return RepoMetadata{}, fmt.Errorf("GitHub API returned %s for %s/%s", resp.Status, owner, repo)
+	}
+
+	var body repoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return RepoMetadata{}, fmt.Errorf("failed to decode GitHub API response: %w", err)
+	}
+
+	metadata := RepoMetadata{
+		Description:   body.Description,
+		Homepage:      body.Homepage,
+		Topics:        body.Topics,
+		Visibility:    body.Visibility,
+		DefaultBranch: body.DefaultBranch,
+	}
+
+	if metadata.Visibility == "" {
+		metadata.Visibility = "public"
+		if body.Private {
+			metadata.Visibility = "private"
+		}
+	}
+
+	if body.License != nil {
+		metadata.License = body.License.SPDXID
+		metadata.LicenseName = body.License.Name
+	}
+
+	return metadata, nil
+}
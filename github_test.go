@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Helper function to start a fake GitHub API server for testing.
+func setupFakeGithubAPI() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer testtoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/repos/testuser/testrepo":
+			fmt.Fprint(w, `{
+  "description": "A test repository",
+  "homepage": "https://example.com",
+  "topics": ["go", "cli"],
+  "visibility": "public",
+  "default_branch": "main",
+  "license": {"spdx_id": "MIT", "name": "MIT License"}
+}`)
+		case "/repos/testuser/private":
+			fmt.Fprint(w, `{"private": true, "description": null, "license": null}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetRepoMetadata(t *testing.T) {
+	server := setupFakeGithubAPI()
+	defer server.Close()
+
+	client := &GithubClient{BaseURL: server.URL, Token: "testtoken", HTTPClient: server.Client()}
+
+	metadata, err := client.GetRepoMetadata(context.Background(), "testuser", "testrepo")
+	if err != nil {
+		t.Fatalf("Failed to get repository metadata: %v", err)
+	}
+
+	if metadata.Description != "A test repository" || metadata.Homepage != "https://example.com" {
+		t.Errorf("Unexpected description or homepage: %+v", metadata)
+	}
+
+	if metadata.License != "MIT" || metadata.LicenseName != "MIT License" {
+		t.Errorf("Unexpected license: %+v", metadata)
+	}
+
+	if strings.Join(metadata.Topics, ",") != "go,cli" {
+		t.Errorf("Expected topics [go cli], got %v", metadata.Topics)
+	}
+
+	if metadata.Visibility != "public" || metadata.DefaultBranch != "main" {
+		t.Errorf("Unexpected visibility or default branch: %+v", metadata)
+	}
+
+	metadata, err = client.GetRepoMetadata(context.Background(), "testuser", "private")
+	if err != nil {
+		t.Fatalf("Failed to get repository metadata: %v", err)
+	}
+
+	if metadata.Visibility != "private" || metadata.Description != "" || metadata.License != "" {
+		t.Errorf("Unexpected metadata for private repository: %+v", metadata)
+	}
+}
+
+func TestGetRepoMetadata_Error(t *testing.T) {
+	server := setupFakeGithubAPI()
+	defer server.Close()
+
+	testCases := []struct {
+		name, token, repo string
+	}{
+		{"not found", "testtoken", "missing"},
+		{"unauthorized", "", "testrepo"},
+		{"escaped name", "testtoken", "testrepo?page=1"},
+	}
+
+	for _, tc := range testCases {
+		client := &GithubClient{BaseURL: server.URL, Token: tc.token, HTTPClient: server.Client()}
+
+		if _, err := client.GetRepoMetadata(context.Background(), "testuser", tc.repo); err == nil {
+			t.Errorf("%s: Expected error, got nil", tc.name)
+		}
+	}
+}
+
+func TestNewGithubClient(t *testing.T) {
+	t.Setenv("GH_TOKEN", "dotcomtoken")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "enterprisetoken")
+
+	testCases := []struct {
+		host, baseURL, token string
+	}{
+		{"github.com", "https://api.github.com", "dotcomtoken"},
+		{"ghe.corp.example", "https://ghe.corp.example/api/v3", "enterprisetoken"},
+		{"ghe.corp.example:8443", "https://ghe.corp.example:8443/api/v3", "enterprisetoken"},
+	}
+
+	for _, tc := range testCases {
+		client := NewGithubClient(tc.host)
+		if client.BaseURL != tc.baseURL || client.Token != tc.token {
+			t.Errorf("Expected client for %s to use %s with %s, got %s with %s",
+				tc.host, tc.baseURL, tc.token, client.BaseURL, client.Token)
+		}
+	}
+}
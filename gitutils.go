@@ -2,13 +2,25 @@ package main
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
 // Number of parts expected in the GitHub URL.
 const expectedGithubURLParts = 2
 
+// fallbackDefaultBranch is the default branch assumed when it cannot be determined.
+const fallbackDefaultBranch = "main"
+
+// Names of the remotes preferred when no remote is configured.
+const (
+	defaultRemote  = "origin"
+	upstreamRemote = "upstream"
+)
+
 // IsGitRepository checks if the current directory is a git repository.
 func IsGitRepository() bool {
 	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
@@ -30,24 +42,205 @@ func GetGitRoot() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-// GetGithubUserRepo returns the GitHub username and repository name.
-func GetGithubUserRepo() (string, string, error) {
-	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+// GetGithubUserRepo returns the host, GitHub username and repository name of a remote.
+// When remoteName is empty, origin is used, falling back to upstream and then to any other remote.
+// The remote must point to one of allowedHosts.
+func GetGithubUserRepo(remoteName string, allowedHosts []string) (RemoteURL, error) {
+	if remoteName == "" {
+		remoteName = defaultRemoteName()
+	}
+
+	cmd := exec.Command("git", "config", "--get", "remote."+remoteName+".url")
 
 	output, err := cmd.Output()
 	if err != nil {
-		return "", "", errors.New("unable to get remote origin URL")
+		return RemoteURL{}, &NoRemoteError{Remote: remoteName}
+	}
+
+	remote, err := ParseRemoteURL(strings.TrimSpace(string(output)))
+	if err != nil {
+		return RemoteURL{}, err
+	}
+
+	hostname := (&url.URL{Host: remote.Host}).Hostname()
+
+	for _, host := range allowedHosts {
+		if strings.EqualFold(remote.Host, host) || strings.EqualFold(hostname, host) {
+			return remote, nil
+		}
+	}
+
+	return RemoteURL{}, fmt.Errorf("remote host %q is not a known GitHub host: add it to hosts in the config file or set GH_HOST", remote.Host)
+}
+
+// NoRemoteError is returned when the requested remote is not configured.
+type NoRemoteError struct {
+	Remote string
+}
+
+func (e *NoRemoteError) Error() string {
+	return fmt.Sprintf("unable to get remote %s URL", e.Remote)
+}
+
+// GetGitConfig returns the value of a git configuration key, or an empty string when it is not set.
+func GetGitConfig(key string) string {
+	output, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+// RepositoryInfo holds metadata about the local git repository.
+type RepositoryInfo struct {
+	RemoteURL     string
+	DefaultBranch string
+	CurrentBranch string
+	RootName      string
+	UserName      string
+	UserEmail     string
+}
+
+// GetRepositoryInfo returns metadata about the repository rooted at gitRoot.
+// When remoteName is empty, the remote is chosen as in GetGithubUserRepo.
+// Values that cannot be determined are left empty.
+func GetRepositoryInfo(gitRoot, remoteName string) RepositoryInfo {
+	if remoteName == "" {
+		remoteName = defaultRemoteName()
+	}
+
+	currentBranch := GetCurrentBranch()
+
+	return RepositoryInfo{
+		RemoteURL:     RedactRemoteURL(GetGitConfig("remote." + remoteName + ".url")),
+		DefaultBranch: GetDefaultBranch(remoteName, currentBranch),
+		CurrentBranch: currentBranch,
+		RootName:      filepath.Base(gitRoot),
+		UserName:      GetGitConfig("user.name"),
+		UserEmail:     GetGitConfig("user.email"),
+	}
+}
+
+// RedactRemoteURL removes credentials from a remote URL, so they are not rendered into generated files.
+// The user name of an http(s) URL is removed as well, since it may hold a token.
+func RedactRemoteURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+
+	if u.Scheme == "http" || u.Scheme == "https" {
+		u.User = nil
+	} else {
+		u.User = url.User(u.User.Username())
+	}
+
+	return u.String()
+}
+
+// GetCurrentBranch returns the name of the checked out branch, or an empty string when HEAD is detached.
+func GetCurrentBranch() string {
+	output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+// GetDefaultBranch returns the branch the remote's HEAD points to.
+// Without a remote HEAD, it falls back to init.defaultBranch, then to currentBranch, then to "main".
+func GetDefaultBranch(remoteName, currentBranch string) string {
+	output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/"+remoteName+"/HEAD").Output()
+	if err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(output)), remoteName+"/")
+	}
+
+	if branch := GetGitConfig("init.defaultBranch"); branch != "" {
+		return branch
+	}
+
+	if currentBranch != "" {
+		return currentBranch
+	}
+
+	return fallbackDefaultBranch
+}
+
+// defaultRemoteName returns the remote to read when none is configured.
+func defaultRemoteName() string {
+	output, err := exec.Command("git", "remote").Output()
+	if err != nil {
+		return defaultRemote
+	}
+
+	remotes := strings.Fields(string(output))
+
+	for _, preferred := range []string{defaultRemote, upstreamRemote} {
+		for _, remote := range remotes {
+			if remote == preferred {
+				return remote
+			}
+		}
+	}
+
+	if len(remotes) > 0 {
+		return remotes[0]
+	}
+
+	return defaultRemote
+}
+
+// RemoteURL holds the parts of a git remote URL that identify a repository.
+type RemoteURL struct {
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// defaultHTTPPorts are the ports left out of the host of http(s) remotes.
+var defaultHTTPPorts = map[string]string{"http": "80", "https": "443"}
+
+// ParseRemoteURL parses a git remote URL in any of the forms accepted by git:
+// scp-like ("git@github.com:owner/repo.git"), ssh://, git://, and http(s):// with optional userinfo.
+func ParseRemoteURL(rawURL string) (RemoteURL, error) {
+	var host, pth string
+
+	if scheme, _, found := strings.Cut(rawURL, "://"); found && scheme != "" {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return RemoteURL{}, errors.New("invalid GitHub URL")
+		}
+
+		host, pth = u.Hostname(), u.Path
+
+		// The port of an http(s) remote is kept, since the API of a GitHub Enterprise Server is served on it.
+		if defaultPort, ok := defaultHTTPPorts[u.Scheme]; ok && u.Port() != "" && u.Port() != defaultPort {
+			host = u.Host
+		}
+	} else {
+		// scp-like syntax: [user@]host:path
+		address, p, found := strings.Cut(rawURL, ":")
+		if !found || strings.Contains(address, "/") {
+			return RemoteURL{}, errors.New("invalid GitHub URL")
+		}
+
+		if _, h, hasUser := strings.Cut(address, "@"); hasUser {
+			address = h
+		}
+
+		host, pth = address, p
 	}
 
-	url := strings.TrimSpace(string(output))
-	url = strings.TrimPrefix(url, "https://github.com/")
-	url = strings.TrimSuffix(url, ".git")
+	pth = strings.Trim(pth, "/")
+	pth = strings.TrimSuffix(pth, ".git")
 
-	parts := strings.Split(url, "/")
+	parts := strings.Split(pth, "/")
 
-	if len(parts) != expectedGithubURLParts {
-		return "", "", errors.New("invalid GitHub URL")
+	if host == "" || len(parts) != expectedGithubURLParts || parts[0] == "" || parts[1] == "" {
+		return RemoteURL{}, errors.New("invalid GitHub URL")
 	}
 
-	return parts[0], parts[1], nil
+	return RemoteURL{Host: strings.ToLower(host), Owner: parts[0], Repo: parts[1]}, nil
 }
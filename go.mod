@@ -4,10 +4,12 @@ go 1.21
 
 require (
 	github.com/kr/pretty v0.1.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 )
 
 require (
 	github.com/kr/text v0.2.0 // indirect
+	golang.org/x/term v0.15.0
 	gopkg.in/yaml.v3 v3.0.1
 )
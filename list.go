@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+// Padding between the columns of the template list.
+const listColumnPadding = 2
+
+// ListTemplates prints the configured templates with their template and output files.
+// Inside a git repository, relative template files are resolved against its root, as when generating.
+func ListTemplates(w io.Writer) error {
+	config, err := LoadConfig(GetConfigPath())
+	if err != nil {
+		return err
+	}
+
+	var gitRoot string
+
+	if IsGitRepository() {
+		gitRoot, err = GetGitRoot()
+		if err != nil {
+			return err
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, listColumnPadding, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTEMPLATE\tOUTPUT\tSTATUS")
+
+	for _, name := range config.TemplateNames() {
+		templateConfig := config.Templates[name]
+
+		templatePath, status := describeTemplateSource(templateConfig.TemplateFile, gitRoot)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, templatePath, templateConfig.OutputFile, status)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write template list: %w", err)
+	}
+
+	return nil
+}
+
+// describeTemplateSource returns the expanded template path and whether it exists.
+// A relative path is resolved against gitRoot, unless it is empty.
+func describeTemplateSource(templateFile, gitRoot string) (string, string) {
+	templatePath, err := ExpandTilde(templateFile)
+	if err != nil {
+		return templateFile, err.Error()
+	}
+
+	if gitRoot != "" && !filepath.IsAbs(templatePath) {
+		templatePath = filepath.Join(gitRoot, templatePath)
+	}
+
+	if _, err := os.Stat(templatePath); err != nil {
+		return templatePath, "missing"
+	}
+
+	return templatePath, "ok"
+}
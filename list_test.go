@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListTemplates(t *testing.T) {
+	dir, err := os.MkdirTemp("", "testconfig")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	templatePath := filepath.Join(dir, "issue.md")
+	if err := os.WriteFile(templatePath, []byte(""), 0o600); err != nil {
+		t.Fatalf("Failed to write template file: %v", err)
+	}
+
+	configContent := `
+templates:
+  pr:
+    template_file: ~/missing.md
+    output_file: .github/PULL_REQUEST_TEMPLATE.md
+  issue:
+    template_file: ~/issue.md
+    output_file: .github/ISSUE_TEMPLATE.md
+`
+	createTempConfigFileGenerate(t, dir, configContent)
+
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	out := new(bytes.Buffer)
+	if err := ListTemplates(out); err != nil {
+		t.Fatalf("Failed to list templates: %v", err)
+	}
+
+	expected := [][]string{
+		{"NAME", "TEMPLATE", "OUTPUT", "STATUS"},
+		{"issue", templatePath, ".github/ISSUE_TEMPLATE.md", "ok"},
+		{"pr", filepath.Join(dir, "missing.md"), ".github/PULL_REQUEST_TEMPLATE.md", "missing"},
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), out.String())
+	}
+
+	for i, line := range lines {
+		if got := strings.Join(strings.Fields(line), " "); got != strings.Join(expected[i], " ") {
+			t.Errorf("Expected line %q, got %q", strings.Join(expected[i], " "), got)
+		}
+	}
+}
+
+func TestListTemplates_FromSubdirectory(t *testing.T) {
+	configContent := `
+templates:
+  issue:
+    template_file: templates/issue.md
+    output_file: .github/ISSUE_TEMPLATE.md
+`
+	dir, cleanup := setupGenerateTest(t, configContent)
+	defer cleanup()
+
+	if err := os.MkdirAll(filepath.Join(dir, "templates"), 0o755); err != nil {
+		t.Fatalf("Failed to create templates directory: %v", err)
+	}
+
+	createTempTemplateFileGenerate(t, filepath.Join(dir, "templates"), "issue.md", "")
+
+	gitRoot, err := GetGitRoot()
+	if err != nil {
+		t.Fatalf("Failed to get git root: %v", err)
+	}
+
+	if err := os.Chdir(filepath.Join(dir, "templates")); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	out := new(bytes.Buffer)
+	if err := ListTemplates(out); err != nil {
+		t.Fatalf("Failed to list templates: %v", err)
+	}
+
+	expected := strings.Join([]string{"issue", filepath.Join(gitRoot, "templates", "issue.md"),
+		".github/ISSUE_TEMPLATE.md", "ok"}, " ")
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 || strings.Join(strings.Fields(lines[1]), " ") != expected {
+		t.Errorf("Expected line %q, got %q", expected, out.String())
+	}
+}
+
+func TestListTemplates_ConfigError(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(os.TempDir(), "nonexistent-config"))
+
+	if err := ListTemplates(new(bytes.Buffer)); err == nil {
+		t.Fatalf("Expected error for missing config file, got nil")
+	}
+}
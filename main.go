@@ -5,6 +5,6 @@ import (
 )
 
 func main() {
-	cli := &Cli{os.Stdout, os.Stderr}
+	cli := &Cli{os.Stdout, os.Stderr, os.Stdin}
 	os.Exit(cli.Run())
 }
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OverwritePolicy determines what happens when an output file already exists.
+type OverwritePolicy string
+
+const (
+	// OverwritePrompt asks before overwriting and fails when no terminal is available.
+	OverwritePrompt OverwritePolicy = "prompt"
+	// OverwriteForce overwrites existing files without asking.
+	OverwriteForce OverwritePolicy = "force"
+	// OverwriteSkip leaves existing files untouched.
+	OverwriteSkip OverwritePolicy = "skip"
+	// OverwriteBackup copies existing files aside before overwriting them.
+	OverwriteBackup OverwritePolicy = "backup"
+)
+
+// backupSuffix is appended to the name of a backed up file.
+const backupSuffix = ".bak"
+
+// now is a variable for the current time function, so it can be mocked in tests.
+var now = time.Now
+
+// UnmarshalYAML validates the overwrite policy in the configuration file.
+func (p *OverwritePolicy) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return fmt.Errorf("invalid overwrite policy: %w", err)
+	}
+
+	switch policy := OverwritePolicy(s); policy {
+	case OverwritePrompt, OverwriteForce, OverwriteSkip, OverwriteBackup:
+		*p = policy
+	default:
+		return fmt.Errorf("invalid overwrite policy %q: must be one of prompt, force, skip or backup", s)
+	}
+
+	return nil
+}
+
+// resolveOverwritePolicy returns the policy given on the command line, falling back to the
+// template's own policy and finally to prompting.
+func resolveOverwritePolicy(cliPolicy, templatePolicy OverwritePolicy) OverwritePolicy {
+	if cliPolicy != "" {
+		return cliPolicy
+	}
+
+	if templatePolicy != "" {
+		return templatePolicy
+	}
+
+	return OverwritePrompt
+}
+
+// checkOverwrite reports whether outputPath may be written under the given policy.
+// Existing files are backed up first when the policy asks for it.
+func checkOverwrite(outputPath string, policy OverwritePolicy, opts GenerateOptions) (bool, error) {
+	if _, err := os.Stat(outputPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("failed to stat output file: %w", err)
+	}
+
+	switch policy {
+	case OverwriteForce:
+		return true, nil
+	case OverwriteSkip:
+		fmt.Fprintf(opts.OutStream, "Skipped %s: file already exists\n", outputPath)
+
+		return false, nil
+	case OverwriteBackup:
+		backupPath, err := backupFile(outputPath)
+		if err != nil {
+			return false, err
+		}
+
+		fmt.Fprintf(opts.OutStream, "Backed up %s to %s\n", outputPath, backupPath)
+
+		return true, nil
+	case OverwritePrompt:
+		if !opts.Interactive {
+			return false, fmt.Errorf("%s already exists: use --force, --skip-existing or --backup to overwrite it", outputPath)
+		}
+
+		fmt.Fprintf(opts.ErrStream, "Overwrite %s? [y/N] ", outputPath)
+
+		answer, err := readLine(opts.InStream)
+		if err != nil {
+			return false, err
+		}
+
+		if answer := strings.ToLower(answer); answer == "y" || answer == "yes" {
+			return true, nil
+		}
+
+		fmt.Fprintf(opts.OutStream, "Skipped %s\n", outputPath)
+
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid overwrite policy %q", policy)
+	}
+}
+
+// checkConflicts returns an error for the first existing output file that would have to be prompted for
+// when no terminal is available, so that nothing is written when generation would stop part way.
+func checkConflicts(config *Config, templates []string, opts GenerateOptions) error {
+	if opts.Interactive || opts.DryRun || opts.Diff {
+		return nil
+	}
+
+	for _, template := range templates {
+		templateConfig := config.Templates[template]
+		if resolveOverwritePolicy(opts.Overwrite, templateConfig.Overwrite) != OverwritePrompt {
+			continue
+		}
+
+		tempPath, err := GetTemplatePath(config, template)
+		if err != nil {
+			return err
+		}
+
+		files, err := ListTemplateFiles(tempPath, templateConfig.OutputFile)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			if _, err := checkOverwrite(file.OutputPath, OverwritePrompt, opts); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// backupFile copies path to path.bak, or to a timestamped name when that already exists.
+func backupFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file to back up: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file to back up: %w", err)
+	}
+
+	backupPath := path + backupSuffix
+	if _, err := os.Stat(backupPath); err == nil {
+		backupPath = path + "." + now().Format("20060102150405") + backupSuffix
+	}
+
+	if err := os.WriteFile(backupPath, content, info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	return backupPath, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Helper function to create an existing output file for testing.
+func createExistingOutputFile(t *testing.T, content string) (string, func()) {
+	dir, err := os.MkdirTemp("", "testoutput")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+
+	filePath := filepath.Join(dir, "output.txt")
+
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write output file: %v", err)
+	}
+
+	return filePath, func() { os.RemoveAll(dir) }
+}
+
+func TestLoadConfigOverwritePolicy(t *testing.T) {
+	configContent := `
+templates:
+  template1:
+    template_file: "template1.tpl"
+    output_file: "output1.txt"
+    overwrite: skip
+`
+	filePath, cleanup := createTempConfigFile(t, configContent)
+	defer cleanup()
+
+	config, err := LoadConfig(filePath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if config.Templates["template1"].Overwrite != OverwriteSkip {
+		t.Errorf("Expected overwrite policy %q, got %q", OverwriteSkip, config.Templates["template1"].Overwrite)
+	}
+}
+
+func TestLoadConfigInvalidOverwritePolicy(t *testing.T) {
+	configContent := `
+templates:
+  template1:
+    template_file: "template1.tpl"
+    output_file: "output1.txt"
+    overwrite: sometimes
+`
+	filePath, cleanup := createTempConfigFile(t, configContent)
+	defer cleanup()
+
+	_, err := LoadConfig(filePath)
+	if err == nil || !strings.Contains(err.Error(), "invalid overwrite policy") {
+		t.Fatalf("Expected invalid overwrite policy error, got %v", err)
+	}
+}
+
+func TestResolveOverwritePolicy(t *testing.T) {
+	testCases := []struct {
+		cli, template, expected OverwritePolicy
+	}{
+		{"", "", OverwritePrompt},
+		{"", OverwriteSkip, OverwriteSkip},
+		{OverwriteForce, OverwriteSkip, OverwriteForce},
+	}
+
+	for _, tc := range testCases {
+		if got := resolveOverwritePolicy(tc.cli, tc.template); got != tc.expected {
+			t.Errorf("Expected policy %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestCheckOverwrite_NotExist(t *testing.T) {
+	write, err := checkOverwrite(filepath.Join(os.TempDir(), "nonexistent-output.txt"), OverwritePrompt, GenerateOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !write {
+		t.Errorf("Expected missing file to be writable")
+	}
+}
+
+func TestCheckOverwrite(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      OverwritePolicy
+		interactive bool
+		input       string
+		write       bool
+		errMsg      string
+	}{
+		{name: "force", policy: OverwriteForce, write: true},
+		{name: "skip", policy: OverwriteSkip, write: false},
+		{name: "prompt yes", policy: OverwritePrompt, interactive: true, input: "y\n", write: true},
+		{name: "prompt no", policy: OverwritePrompt, interactive: true, input: "n\n", write: false},
+		{name: "prompt default", policy: OverwritePrompt, interactive: true, input: "\n", write: false},
+		{name: "prompt non-interactive", policy: OverwritePrompt, errMsg: "already exists"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputPath, cleanup := createExistingOutputFile(t, "existing")
+			defer cleanup()
+
+			opts := GenerateOptions{
+				Interactive: tc.interactive,
+				OutStream:   new(bytes.Buffer),
+				ErrStream:   new(bytes.Buffer),
+				InStream:    strings.NewReader(tc.input),
+			}
+
+			write, err := checkOverwrite(outputPath, tc.policy, opts)
+			if tc.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+					t.Fatalf("expected error %q, got %v", tc.errMsg, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if write != tc.write {
+				t.Errorf("expected write %v, got %v", tc.write, write)
+			}
+		})
+	}
+}
+
+func TestCheckOverwrite_Backup(t *testing.T) {
+	originalNow := now
+	defer func() { now = originalNow }()
+
+	now = func() time.Time { return time.Date(2024, 7, 1, 12, 30, 0, 0, time.UTC) }
+
+	outputPath, cleanup := createExistingOutputFile(t, "existing")
+	defer cleanup()
+
+	opts := GenerateOptions{OutStream: new(bytes.Buffer)}
+
+	for _, backupPath := range []string{outputPath + ".bak", outputPath + ".20240701123000.bak"} {
+		write, err := checkOverwrite(outputPath, OverwriteBackup, opts)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !write {
+			t.Errorf("Expected file to be writable after backup")
+		}
+
+		content, err := os.ReadFile(backupPath)
+		if err != nil {
+			t.Fatalf("Failed to read backup file: %v", err)
+		}
+
+		if string(content) != "existing" {
+			t.Errorf("Expected backup content to be %q, got %q", "existing", string(content))
+		}
+	}
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/user"
 	"path/filepath"
 	"strings"
 )
 
+// dirPermission is the permission used for directories created for output files.
+const dirPermission = 0o755
+
 // lookupUser is a variable for user lookup function, so it can be mocked in tests.
 var lookupUser = user.Lookup
 
@@ -38,3 +43,35 @@ func ExpandTilde(pth string) (string, error) {
 
 	return pth, nil
 }
+
+// CreateParentDirs creates the missing parent directories of pth and returns them, outermost first.
+func CreateParentDirs(pth string) ([]string, error) {
+	var missing []string
+
+	for dir := filepath.Dir(pth); ; dir = filepath.Dir(dir) {
+		_, err := os.Stat(dir)
+		if err == nil {
+			break
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to stat directory: %w", err)
+		}
+
+		missing = append([]string{dir}, missing...)
+
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(pth), dirPermission); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return missing, nil
+}
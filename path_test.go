@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -64,3 +65,41 @@ func TestExpandTilde(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateParentDirs(t *testing.T) {
+	dir, err := os.MkdirTemp("", "testoutput")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	outputPath := filepath.Join(dir, ".github", "ISSUE_TEMPLATE", "bug.md")
+
+	created, err := CreateParentDirs(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to create parent directories: %v", err)
+	}
+
+	expected := []string{filepath.Join(dir, ".github"), filepath.Join(dir, ".github", "ISSUE_TEMPLATE")}
+	if strings.Join(created, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected created directories %v, got %v", expected, created)
+	}
+
+	info, err := os.Stat(filepath.Dir(outputPath))
+	if err != nil {
+		t.Fatalf("Expected parent directory to exist: %v", err)
+	}
+
+	if info.Mode().Perm() != dirPermission {
+		t.Errorf("Expected directory permission %o, got %o", dirPermission, info.Mode().Perm())
+	}
+
+	created, err = CreateParentDirs(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to create parent directories: %v", err)
+	}
+
+	if len(created) != 0 {
+		t.Errorf("Expected no directories to be created, got %v", created)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ResolveInputs returns vars with a value for every declared input.
+// Missing inputs are prompted for when opts.Interactive is set and take their default otherwise;
+// an error lists the inputs that have neither a value nor a default.
+func ResolveInputs(template string, inputs []TemplateInput, vars map[string]string, opts GenerateOptions) (map[string]string, error) {
+	resolved := MergeVars(vars)
+
+	var missing []string
+
+	for _, input := range inputs {
+		value, ok := resolved[input.Name]
+
+		if !ok && opts.Interactive {
+			answer, err := promptInput(input, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			value, ok = answer, true
+		} else if !ok && input.Default != "" {
+			value, ok = input.Default, true
+		}
+
+		if !ok {
+			missing = append(missing, input.Name)
+			continue
+		}
+
+		if !input.accepts(value) {
+			return nil, fmt.Errorf("invalid value %q for input %q of template %q: must be one of %s",
+				value, input.Name, template, strings.Join(input.Choices, ", "))
+		}
+
+		resolved[input.Name] = value
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing values for inputs of template %q: %s (use --var name=value)",
+			template, strings.Join(missing, ", "))
+	}
+
+	return resolved, nil
+}
+
+// promptInput asks for the value of input until a valid answer is given.
+func promptInput(input TemplateInput, opts GenerateOptions) (string, error) {
+	message := input.Prompt
+	if message == "" {
+		message = input.Name
+	}
+
+	if len(input.Choices) > 0 {
+		message += " (" + strings.Join(input.Choices, "/") + ")"
+	}
+
+	if input.Default != "" {
+		message += " [" + input.Default + "]"
+	}
+
+	for {
+		fmt.Fprintf(opts.ErrStream, "%s: ", message)
+
+		answer, err := readLine(opts.InStream)
+		if err != nil {
+			return "", err
+		}
+
+		if answer == "" {
+			answer = input.Default
+		}
+
+		if answer != "" && input.accepts(answer) {
+			return answer, nil
+		}
+	}
+}
+
+// readLine reads a single line from r without buffering past the newline.
+func readLine(r io.Reader) (string, error) {
+	if r == nil {
+		return "", errors.New("no input available")
+	}
+
+	var line []byte
+
+	buf := make([]byte, 1)
+
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+
+			line = append(line, buf[0])
+		}
+
+		if errors.Is(err, io.EOF) {
+			if len(line) == 0 {
+				return "", errors.New("unexpected end of input")
+			}
+
+			break
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+	}
+
+	return strings.TrimSpace(string(line)), nil
+}
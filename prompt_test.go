@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestResolveInputs(t *testing.T) {
+	inputs := []TemplateInput{
+		{Name: "email", Prompt: "Security contact email"},
+		{Name: "channel", Default: "#general"},
+		{Name: "severity", Prompt: "Default severity", Choices: []string{"low", "high"}, Default: "low"},
+	}
+
+	out := new(bytes.Buffer)
+	opts := GenerateOptions{
+		Interactive: true,
+		ErrStream:   out,
+		// An empty answer takes the default; an invalid choice is asked again.
+		InStream: strings.NewReader("security@example.com\n\nmedium\nhigh\n"),
+	}
+
+	vars, err := ResolveInputs("security", inputs, map[string]string{"other": "value"}, opts)
+	if err != nil {
+		t.Fatalf("Failed to resolve inputs: %v", err)
+	}
+
+	expected := map[string]string{"email": "security@example.com", "channel": "#general", "severity": "high", "other": "value"}
+	for key, value := range expected {
+		if vars[key] != value {
+			t.Errorf("Expected %s to be %q, got %q", key, value, vars[key])
+		}
+	}
+
+	expectedPrompts := "Security contact email: channel [#general]: " +
+		"Default severity (low/high) [low]: Default severity (low/high) [low]: "
+	if out.String() != expectedPrompts {
+		t.Errorf("Expected prompts %q, got %q", expectedPrompts, out.String())
+	}
+}
+
+func TestResolveInputs_NonInteractive(t *testing.T) {
+	inputs := []TemplateInput{
+		{Name: "email"},
+		{Name: "channel", Default: "#general"},
+		{Name: "team"},
+	}
+
+	vars, err := ResolveInputs("security", inputs, map[string]string{"email": "security@example.com", "team": "ops"}, GenerateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to resolve inputs: %v", err)
+	}
+
+	if vars["channel"] != "#general" {
+		t.Errorf("Expected default channel, got %q", vars["channel"])
+	}
+
+	_, err = ResolveInputs("security", inputs, nil, GenerateOptions{})
+	if err == nil || err.Error() != `missing values for inputs of template "security": email, team (use --var name=value)` {
+		t.Errorf("Expected missing inputs error, got %v", err)
+	}
+
+	choices := []TemplateInput{{Name: "severity", Choices: []string{"low", "high"}}}
+
+	_, err = ResolveInputs("security", choices, map[string]string{"severity": "medium"}, GenerateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "must be one of low, high") {
+		t.Errorf("Expected invalid choice error, got %v", err)
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	r := strings.NewReader(" yes \nno\n")
+
+	first, err := readLine(r)
+	if err != nil || first != "yes" {
+		t.Errorf("Expected first line %q, got %q (%v)", "yes", first, err)
+	}
+
+	second, err := readLine(r)
+	if err != nil || second != "no" {
+		t.Errorf("Expected second line %q, got %q (%v)", "no", second, err)
+	}
+
+	if _, err := readLine(r); err == nil {
+		t.Errorf("Expected error at end of input, got nil")
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// fieldChecker validates the field references of a template set against the type of the data it is executed with.
+type fieldChecker struct {
+	tmpl *template.Template
+	// tree is the template being checked, used to report the location of an unknown field.
+	tree *parse.Tree
+	// root is the type of the data the template being checked is executed with, which $ refers to.
+	root    reflect.Type
+	checked map[string]bool
+}
+
+// checkFields returns an error for the first reference to a field that the data passed to tmpl does not have.
+// The templates included by tmpl are checked against the data passed to them.
+// Fields whose type is unknown before execution, such as those of function results, are not checked.
+func checkFields(tmpl *template.Template, data any) error {
+	checker := &fieldChecker{tmpl: tmpl, checked: make(map[string]bool)}
+
+	return checker.checkTemplate(tmpl.Name(), reflect.TypeOf(data))
+}
+
+// checkTemplate checks the template with the given name, executed with data of type dot.
+func (c *fieldChecker) checkTemplate(name string, dot reflect.Type) error {
+	key := fmt.Sprintf("%s:%v", name, dot)
+
+	t := c.tmpl.Lookup(name)
+	if t == nil || t.Tree == nil || c.checked[key] {
+		return nil
+	}
+
+	c.checked[key] = true
+
+	tree, root := c.tree, c.root
+	c.tree, c.root = t.Tree, dot
+
+	defer func() { c.tree, c.root = tree, root }()
+
+	return c.checkList(t.Tree.Root, dot)
+}
+
+func (c *fieldChecker) checkList(list *parse.ListNode, dot reflect.Type) error {
+	if list == nil {
+		return nil
+	}
+
+	for _, node := range list.Nodes {
+		if err := c.checkNode(node, dot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *fieldChecker) checkNode(node parse.Node, dot reflect.Type) error {
+	switch n := node.(type) {
+	case *parse.ActionNode:
+		_, err := c.checkPipe(n.Pipe, dot)
+
+		return err
+	case *parse.IfNode:
+		return c.checkBranch(&n.BranchNode, dot, func(reflect.Type) reflect.Type { return dot })
+	case *parse.WithNode:
+		return c.checkBranch(&n.BranchNode, dot, func(typ reflect.Type) reflect.Type { return typ })
+	case *parse.RangeNode:
+		return c.checkBranch(&n.BranchNode, dot, elemType)
+	case *parse.TemplateNode:
+		typ, err := c.checkPipe(n.Pipe, dot)
+		if err != nil {
+			return err
+		}
+
+		return c.checkTemplate(n.Name, typ)
+	}
+
+	return nil
+}
+
+// checkBranch checks an if, range or with action. inner returns the type of dot inside the action
+// from the type of its pipeline.
+func (c *fieldChecker) checkBranch(
+	branch *parse.BranchNode, dot reflect.Type, inner func(reflect.Type) reflect.Type,
+) error {
+	typ, err := c.checkPipe(branch.Pipe, dot)
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkList(branch.List, inner(typ)); err != nil {
+		return err
+	}
+
+	return c.checkList(branch.ElseList, dot)
+}
+
+// checkPipe checks the arguments of a pipeline and returns its type when it is known,
+// that is when the pipeline is a single field, variable or dot.
+func (c *fieldChecker) checkPipe(pipe *parse.PipeNode, dot reflect.Type) (reflect.Type, error) {
+	if pipe == nil {
+		return nil, nil
+	}
+
+	var typ reflect.Type
+
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			argType, err := c.checkArg(arg, dot)
+			if err != nil {
+				return nil, err
+			}
+
+			typ = argType
+		}
+	}
+
+	if len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return nil, nil
+	}
+
+	return typ, nil
+}
+
+// checkArg checks a command argument and returns its type when it is known.
+func (c *fieldChecker) checkArg(arg parse.Node, dot reflect.Type) (reflect.Type, error) {
+	switch n := arg.(type) {
+	case *parse.DotNode:
+		return dot, nil
+	case *parse.FieldNode:
+		return c.fieldType(n, dot, n.Ident)
+	case *parse.VariableNode:
+		// Only $ has a known type; other variables are assigned from pipelines.
+		if n.Ident[0] == "$" {
+			return c.fieldType(n, c.root, n.Ident[1:])
+		}
+	case *parse.PipeNode:
+		return c.checkPipe(n, dot)
+	case *parse.ChainNode:
+		typ, err := c.checkArg(n.Node, dot)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.fieldType(n, typ, n.Field)
+	}
+
+	return nil, nil
+}
+
+// fieldType returns the type of the chain of fields in typ, or an error when a field does not exist.
+func (c *fieldChecker) fieldType(node parse.Node, typ reflect.Type, fields []string) (reflect.Type, error) {
+	for _, field := range fields {
+		if typ == nil {
+			return nil, nil
+		}
+
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		// Methods may take arguments, so the type of their result is not followed.
+		if _, ok := typ.MethodByName(field); ok {
+			return nil, nil
+		}
+
+		switch typ.Kind() {
+		case reflect.Struct:
+			structField, ok := typ.FieldByName(field)
+			if !ok || !structField.IsExported() {
+				return nil, c.unknownFieldError(node, typ, field)
+			}
+
+			typ = structField.Type
+		case reflect.Map:
+			// A missing key is reported on execution, with missingkey=error.
+			typ = typ.Elem()
+		case reflect.Interface:
+			return nil, nil
+		default:
+			return nil, c.unknownFieldError(node, typ, field)
+		}
+	}
+
+	return typ, nil
+}
+
+// unknownFieldError returns an error locating the reference to field, which typ does not have.
+func (c *fieldChecker) unknownFieldError(node parse.Node, typ reflect.Type, field string) error {
+	location, _ := c.tree.ErrorContext(node)
+
+	name := typ.Name()
+	if name == "" {
+		name = typ.String()
+	}
+
+	message := fmt.Sprintf("%s: %s has no field %s", location, name, field)
+
+	if typ.Kind() == reflect.Struct {
+		var names []string
+
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).IsExported() {
+				names = append(names, typ.Field(i).Name)
+			}
+		}
+
+		if suggestions := SuggestNames(field, names); len(suggestions) > 0 {
+			message += fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, ", "))
+		}
+	}
+
+	return errors.New(message)
+}
+
+// elemType returns the type of the elements ranged over in typ, or nil when it is unknown.
+func elemType(typ reflect.Type) reflect.Type {
+	if typ == nil {
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return typ.Elem()
+	default:
+		return nil
+	}
+}
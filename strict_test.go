@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestCheckFields(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"fields", `{{.Repository}} {{.Repo.Description}} {{.Vars.anything}} {{$.Username}}`, ""},
+		{"pipelines", `{{.Repo.Topics | join "," | upper}} {{(.Repo).License}}`, ""},
+		{"with and range", `{{with .Repo}}{{.Homepage}}{{end}}{{range .Repo.Topics}}{{.}}{{end}}`, ""},
+		{"unknown dot", `{{define "badge"}}{{.Anything}}{{end}}{{template "badge" (upper .Repository)}}`, ""},
+		{"typo", `{{.Reponame}}`, "issue.md:1:2: TemplateData has no field Reponame"},
+		{"nested", `{{with .Repo}}{{.Descripton}}{{end}}`, "RepoMetadata has no field Descripton (did you mean Description?)"},
+		{"range element", `{{range .Repo.Topics}}{{.Name}}{{end}}`, "string has no field Name"},
+		{"root variable", `{{range $topic := .Repo.Topics}}{{$.Usernme}}{{end}}`, "TemplateData has no field Usernme (did you mean Username?)"},
+		{"else branch", `{{if .Username}}{{else}}{{.Owner}}{{end}}`, "TemplateData has no field Owner"},
+		{"included template", `{{define "footer"}}{{.GitUser}}{{end}}{{template "footer" .}}`, "issue.md:1:21: TemplateData has no field GitUser"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := template.New("issue.md").Funcs(templateFuncs(RenderOptions{})).Parse(tc.content)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+
+			err = checkFields(tmpl, TemplateData{})
+
+			switch {
+			case tc.expected == "" && err != nil:
+				t.Errorf("expected no error, got %v", err)
+			case tc.expected != "" && (err == nil || !strings.Contains(err.Error(), tc.expected)):
+				t.Errorf("expected error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
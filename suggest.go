@@ -0,0 +1,65 @@
+package main
+
+import (
+	"sort"
+)
+
+// Divisor of the name length used to derive how many edits a suggestion may be away.
+const suggestionDistanceDivisor = 3
+
+// SuggestNames returns the candidates closest to name by edit distance.
+// Only candidates within a distance proportional to the length of name are suggested.
+func SuggestNames(name string, candidates []string) []string {
+	maxDistance := len(name)/suggestionDistanceDivisor + 1
+	best := maxDistance + 1
+
+	var suggestions []string
+
+	for _, candidate := range candidates {
+		distance := levenshtein(name, candidate)
+		if distance > maxDistance {
+			continue
+		}
+
+		switch {
+		case distance < best:
+			best = distance
+			suggestions = []string{candidate}
+		case distance == best:
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	sort.Strings(suggestions)
+
+	return suggestions
+}
+
+// levenshtein returns the number of single-character edits needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"issue", "issue", 0},
+		{"isue", "issue", 1},
+		{"kitten", "sitting", 3},
+		{"", "pr", 2},
+	}
+
+	for _, tc := range testCases {
+		if got := levenshtein(tc.a, tc.b); got != tc.expected {
+			t.Errorf("Expected distance between %q and %q to be %d, got %d", tc.a, tc.b, tc.expected, got)
+		}
+	}
+}
+
+func TestSuggestNames(t *testing.T) {
+	candidates := []string{"issue", "pr", "security", "template1", "template2"}
+
+	testCases := []struct {
+		name     string
+		expected []string
+	}{
+		{"isue", []string{"issue"}},
+		{"pt", []string{"pr"}},
+		{"template", []string{"template1", "template2"}},
+		{"workflow", nil},
+		{"xy", nil},
+	}
+
+	for _, tc := range testCases {
+		got := SuggestNames(tc.name, candidates)
+		if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("Expected suggestions for %q to be %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
@@ -2,43 +2,353 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"text/template"
+	"text/template/parse"
 )
 
 // TemplateData holds the data to be inserted into the template.
 type TemplateData struct {
-	Username   string
-	Repository string
+	Username      string
+	Repository    string
+	Host          string
+	RemoteURL     string
+	DefaultBranch string
+	CurrentBranch string
+	RootName      string
+	GitUserName   string
+	GitUserEmail  string
+	// Repo is only populated with --online.
+	Repo RepoMetadata
+	Vars map[string]string
 }
 
-const permission = 0o600
+// RenderOptions controls how templates are parsed and executed.
+type RenderOptions struct {
+	// EnvAllowlist restricts the environment variables readable with env. All are readable when it is nil.
+	EnvAllowlist []string
+	// Partials are parsed into the same template set, each named after its file without the extension.
+	Partials []string
+	// Base is the template the rendered file extends. Its blocks can be overridden with {{ define }}.
+	Base string
+	// Delims are the action delimiters of the template file. The partials always use the defaults.
+	Delims Delims
+	// BaseDelims are the action delimiters of the base.
+	BaseDelims Delims
+	// Strict fails on references to fields and variables that do not exist instead of rendering "<no value>".
+	Strict bool
+}
+
+// TemplateFile pairs a template file with the file generated from it.
+type TemplateFile struct {
+	TemplatePath string
+	OutputPath   string
+}
+
+// defaultFileMode is the permission of generated files when the template does not set one.
+const defaultFileMode = 0o644
 
 // GenerateFileFromTemplate generates a file from a template with the provided data.
-func GenerateFileFromTemplate(templatePath, outputPath, username, repository string) error {
-	data := TemplateData{
-		Username:   username,
-		Repository: repository,
+func GenerateFileFromTemplate(templatePath, outputPath string, data TemplateData, mode os.FileMode, opts RenderOptions) error {
+	content, err := RenderTemplate(templatePath, data, opts)
+	if err != nil {
+		return err
+	}
+
+	return writeOutputFile(outputPath, content, mode)
+}
+
+// CopyFile copies a template file to the output file as is, without rendering it.
+func CopyFile(templatePath, outputPath string, mode os.FileMode) error {
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	return writeOutputFile(outputPath, content, mode)
+}
+
+// writeOutputFile writes content to the output file and sets its permission.
+func writeOutputFile(outputPath string, content []byte, mode os.FileMode) error {
+	if err := os.WriteFile(outputPath, content, mode); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	// os.WriteFile only applies the mode to new files.
+	if err := os.Chmod(outputPath, mode); err != nil {
+		return fmt.Errorf("failed to set output file mode: %w", err)
+	}
+
+	return nil
+}
+
+// IsVerbatim reports whether a template file is copied as is instead of being rendered,
+// either because the template sets copy or because the file is binary.
+func IsVerbatim(templateConfig TemplateConfig, templatePath string) (bool, error) {
+	if templateConfig.Copy {
+		return true, nil
+	}
+
+	file, err := os.Open(templatePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open template file: %w", err)
+	}
+	defer file.Close()
+
+	head := make([]byte, binarySniffLength)
+
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false, fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	return isBinary(head[:n]), nil
+}
+
+// MergeVars merges template variables, with later maps taking precedence over earlier ones.
+func MergeVars(varMaps ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+
+	for _, vars := range varMaps {
+		for key, value := range vars {
+			merged[key] = value
+		}
 	}
 
-	tmpl, err := template.ParseFiles(templatePath)
+	return merged
+}
+
+// RenderTemplate renders a template with the provided data and returns the result.
+func RenderTemplate(templatePath string, data TemplateData, opts RenderOptions) ([]byte, error) {
+	tmpl, err := parseTemplate(templatePath, opts)
 	if err != nil {
-		return fmt.Errorf("failed to parse template file: %w", err)
+		return nil, err
+	}
+
+	if err := checkTemplateReferences(tmpl); err != nil {
+		return nil, err
+	}
+
+	if opts.Strict {
+		if err := checkFields(tmpl, data); err != nil {
+			return nil, err
+		}
 	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+		return nil, fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	if err := os.WriteFile(outputPath, buf.Bytes(), permission); err != nil {
-		return fmt.Errorf("failed to write output file: %w", err)
+	return buf.Bytes(), nil
+}
+
+// parseTemplate parses a template file and the partials into one set.
+// When opts.Base is set, the set is rooted at the base template and the file is parsed after it,
+// so executing the set renders the base with the blocks the file overrides.
+func parseTemplate(templatePath string, opts RenderOptions) (*template.Template, error) {
+	root := templatePath
+	if opts.Base != "" {
+		root = opts.Base
+	}
+
+	// A base is shared by templates with different delimiters, so it has delimiters of its own.
+	rootDelims := opts.Delims
+	if opts.Base != "" {
+		rootDelims = opts.BaseDelims
+	}
+
+	tmpl := template.New(filepath.Base(root)).Delims(rootDelims.Left, rootDelims.Right).Funcs(templateFuncs(opts))
+
+	if opts.Strict {
+		tmpl.Option("missingkey=error")
+	}
+
+	tmpl, err := tmpl.ParseFiles(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template file: %w", err)
+	}
+
+	if err := parsePartials(tmpl, opts.Partials); err != nil {
+		return nil, err
+	}
+
+	if opts.Base == "" {
+		return tmpl, nil
+	}
+
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	// The file is named after its path, so it cannot replace a base with the same file name.
+	if _, err := tmpl.New(templatePath).Delims(opts.Delims.Left, opts.Delims.Right).Parse(string(content)); err != nil {
+		return nil, fmt.Errorf("failed to parse template file: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// parsePartials adds each partial file to tmpl, so templates can include it with {{ template "name" . }}.
+// Templates defined in a partial with {{ define }} can be included as well.
+func parsePartials(tmpl *template.Template, partials []string) error {
+	for _, partial := range partials {
+		content, err := os.ReadFile(partial)
+		if err != nil {
+			return fmt.Errorf("failed to read partial file: %w", err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(partial), filepath.Ext(partial))
+		// Partials are shared by templates with different delimiters, so they always use the defaults.
+		if _, err := tmpl.New(name).Delims("", "").Parse(string(content)); err != nil {
+			return fmt.Errorf("failed to parse partial file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkTemplateReferences returns an error when a template includes a template that is not defined,
+// so a missing partial is reported before anything is rendered.
+func checkTemplateReferences(tmpl *template.Template) error {
+	var names []string
+
+	for _, t := range tmpl.Templates() {
+		names = append(names, t.Name())
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		var missing string
+
+		walkNodes(tmpl.Lookup(name).Tree.Root, func(node parse.Node) {
+			if ref, ok := node.(*parse.TemplateNode); ok && missing == "" && tmpl.Lookup(ref.Name) == nil {
+				missing = ref.Name
+			}
+		})
+
+		if missing == "" {
+			continue
+		}
+
+		message := fmt.Sprintf("template %s includes undefined partial %q", name, missing)
+		if suggestions := SuggestNames(missing, names); len(suggestions) > 0 {
+			message += fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, ", "))
+		}
+
+		return errors.New(message + ": add it to the partials in the config file")
 	}
 
 	return nil
 }
 
+// walkNodes calls fn for node and every node in the lists beneath it.
+func walkNodes(node parse.Node, fn func(parse.Node)) {
+	fn(node)
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			walkNodes(child, fn)
+		}
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, fn)
+	}
+}
+
+// walkBranch calls walkNodes for both lists of an if, range or with action.
+func walkBranch(branch *parse.BranchNode, fn func(parse.Node)) {
+	walkNodes(branch.List, fn)
+
+	if branch.ElseList != nil {
+		walkNodes(branch.ElseList, fn)
+	}
+}
+
+// ListPartialFiles returns the partial files matched by pattern, which is a directory or a glob.
+func ListPartialFiles(pattern string) ([]string, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+
+	pattern, err := ExpandTilde(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		pattern = filepath.Join(pattern, "*")
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid partials pattern %q: %w", pattern, err)
+	}
+
+	var files []string
+
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat partial file: %w", err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, match)
+		}
+	}
+
+	return files, nil
+}
+
+// ListTemplateFiles returns the files to generate for a template.
+// When templatePath is a directory, every file beneath it is rendered into the same layout under outputPath.
+func ListTemplateFiles(templatePath, outputPath string) ([]TemplateFile, error) {
+	// A missing template file is reported by the template parser.
+	if info, err := os.Stat(templatePath); err != nil || !info.IsDir() {
+		return []TemplateFile{{TemplatePath: templatePath, OutputPath: outputPath}}, nil
+	}
+
+	var files []TemplateFile
+
+	err := filepath.WalkDir(templatePath, func(pth string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(templatePath, pth)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, TemplateFile{TemplatePath: pth, OutputPath: filepath.Join(outputPath, rel)})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk template directory: %w", err)
+	}
+
+	return files, nil
+}
+
 // GetTemplatePath returns the full path of a template file based on the config directory.
 func GetTemplatePath(config *Config, templateName string) (string, error) {
 	templatePath, err := ExpandTilde(config.Templates[templateName].TemplateFile)
@@ -48,3 +358,24 @@ func GetTemplatePath(config *Config, templateName string) (string, error) {
 
 	return templatePath, nil
 }
+
+// GetFileMode returns the permission to use for the output file of a template.
+// defaultMode is used when the template neither sets nor preserves one.
+func GetFileMode(templateConfig TemplateConfig, outputPath string, defaultMode os.FileMode) (os.FileMode, error) {
+	if templateConfig.PreserveMode {
+		info, err := os.Stat(outputPath)
+		if err == nil {
+			return info.Mode().Perm(), nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("failed to stat output file: %w", err)
+		}
+	}
+
+	if templateConfig.Mode != 0 {
+		return os.FileMode(templateConfig.Mode), nil
+	}
+
+	return defaultMode, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -52,7 +53,7 @@ func TestGenerateFileFromTemplate(t *testing.T) {
 	user := "testuser"
 	repo := "testrepo"
 
-	if err := GenerateFileFromTemplate(templatePath, outputPath, user, repo); err != nil {
+	if err := GenerateFileFromTemplate(templatePath, outputPath, TemplateData{Username: user, Repository: repo}, defaultFileMode, RenderOptions{}); err != nil {
 		t.Fatalf("Failed to generate file from template: %v", err)
 	}
 
@@ -67,6 +68,265 @@ func TestGenerateFileFromTemplate(t *testing.T) {
 	}
 }
 
+func TestRenderTemplate(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	templatePath, cleanupTemplate := createTempTemplateFile(t, tempDir, `{{.Host}}/{{.Username}}/{{.Repository}}`)
+	defer cleanupTemplate()
+
+	data := TemplateData{Username: "testuser", Repository: "testrepo", Host: "github.com"}
+
+	content, err := RenderTemplate(templatePath, data, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	expectedContent := "github.com/testuser/testrepo"
+	if string(content) != expectedContent {
+		t.Errorf("Expected rendered content to be %s, got %s", expectedContent, string(content))
+	}
+}
+
+func TestRenderTemplate_Env(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	templatePath, cleanupTemplate := createTempTemplateFile(t, tempDir, `{{env "ORG_SECURITY_EMAIL"}} {{env "ORG_SLACK" "#general"}}`)
+	defer cleanupTemplate()
+
+	t.Setenv("ORG_SECURITY_EMAIL", "security@example.com")
+	t.Setenv("ORG_SLACK", "")
+
+	content, err := RenderTemplate(templatePath, TemplateData{}, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	expectedContent := "security@example.com #general"
+	if string(content) != expectedContent {
+		t.Errorf("Expected rendered content to be %s, got %s", expectedContent, string(content))
+	}
+
+	_, err = RenderTemplate(templatePath, TemplateData{}, RenderOptions{EnvAllowlist: []string{"ORG_SLACK"}})
+	if err == nil {
+		t.Fatalf("Expected error for environment variable outside the allowlist, got nil")
+	}
+}
+
+func TestRenderTemplate_Partials(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	footerPath := filepath.Join(tempDir, "footer.md")
+	if err := os.WriteFile(footerPath, []byte(`Maintained by {{.Username}}`), 0o600); err != nil {
+		t.Fatalf("Failed to write partial file: %v", err)
+	}
+
+	helpersPath := filepath.Join(tempDir, "helpers.tpl")
+	if err := os.WriteFile(helpersPath, []byte(`{{define "badge"}}[{{.}}]{{end}}`), 0o600); err != nil {
+		t.Fatalf("Failed to write partial file: %v", err)
+	}
+
+	opts := RenderOptions{Partials: []string{footerPath, helpersPath}}
+
+	templatePath, cleanupTemplate := createTempTemplateFile(t, tempDir, `{{template "badge" .Repository}} {{template "footer" .}}`)
+	defer cleanupTemplate()
+
+	content, err := RenderTemplate(templatePath, TemplateData{Username: "testuser", Repository: "testrepo"}, opts)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	expectedContent := "[testrepo] Maintained by testuser"
+	if string(content) != expectedContent {
+		t.Errorf("Expected rendered content to be %s, got %s", expectedContent, string(content))
+	}
+
+	templatePath, cleanupTemplate = createTempTemplateFile(t, tempDir, `{{if .Username}}{{template "footr" .}}{{end}}`)
+	defer cleanupTemplate()
+
+	_, err = RenderTemplate(templatePath, TemplateData{}, opts)
+	if err == nil || !strings.Contains(err.Error(), `undefined partial "footr" (did you mean footer?)`) {
+		t.Errorf("Expected undefined partial error, got %v", err)
+	}
+}
+
+func TestRenderTemplate_Extends(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	basePath := filepath.Join(tempDir, "base.md")
+	baseContent := `# {{.Repository}}
+{{block "summary" .}}No summary.{{end}}
+{{block "steps" .}}No steps.{{end}}`
+
+	if err := os.WriteFile(basePath, []byte(baseContent), 0o600); err != nil {
+		t.Fatalf("Failed to write base template file: %v", err)
+	}
+
+	templatePath, cleanupTemplate := createTempTemplateFile(t, tempDir, `{{define "steps"}}1. Run {{.Username}}/{{.Repository}}{{end}}`)
+	defer cleanupTemplate()
+
+	data := TemplateData{Username: "testuser", Repository: "testrepo"}
+
+	content, err := RenderTemplate(templatePath, data, RenderOptions{Base: basePath})
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	expectedContent := "# testrepo\nNo summary.\n1. Run testuser/testrepo"
+	if string(content) != expectedContent {
+		t.Errorf("Expected rendered content to be %q, got %q", expectedContent, string(content))
+	}
+
+	_, err = RenderTemplate(templatePath, data, RenderOptions{Base: filepath.Join(tempDir, "missing.md")})
+	if err == nil {
+		t.Errorf("Expected error for missing base template, got nil")
+	}
+}
+
+func TestListPartialFiles(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	partialsDir := filepath.Join(tempDir, "partials")
+	if err := os.MkdirAll(filepath.Join(partialsDir, "nested"), 0o755); err != nil {
+		t.Fatalf("Failed to create partials directory: %v", err)
+	}
+
+	for _, name := range []string{"footer.md", "header.md", "helpers.tpl"} {
+		if err := os.WriteFile(filepath.Join(partialsDir, name), []byte(""), 0o600); err != nil {
+			t.Fatalf("Failed to write partial file: %v", err)
+		}
+	}
+
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"", nil},
+		{partialsDir, []string{"footer.md", "header.md", "helpers.tpl"}},
+		{filepath.Join(partialsDir, "*.md"), []string{"footer.md", "header.md"}},
+		{filepath.Join(tempDir, "missing"), nil},
+	}
+
+	for _, tc := range testCases {
+		files, err := ListPartialFiles(tc.pattern)
+		if err != nil {
+			t.Fatalf("Failed to list partial files: %v", err)
+		}
+
+		var names []string
+		for _, file := range files {
+			names = append(names, filepath.Base(file))
+		}
+
+		if strings.Join(names, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("Expected %q to match %v, got %v", tc.pattern, tc.expected, names)
+		}
+	}
+}
+
+func TestRenderTemplate_Delims(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	footerPath := filepath.Join(tempDir, "footer.md")
+	if err := os.WriteFile(footerPath, []byte(`# {{.Username}}`), 0o600); err != nil {
+		t.Fatalf("Failed to write partial file: %v", err)
+	}
+
+	templatePath, cleanupTemplate := createTempTemplateFile(t, tempDir, `repo: [[.Repository]]
+ref: ${{ github.ref }}
+[[template "footer" .]]`)
+	defer cleanupTemplate()
+
+	opts := RenderOptions{Delims: Delims{"[[", "]]"}, Partials: []string{footerPath}}
+
+	content, err := RenderTemplate(templatePath, TemplateData{Username: "testuser", Repository: "testrepo"}, opts)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	expectedContent := "repo: testrepo\nref: ${{ github.ref }}\n# testuser"
+	if string(content) != expectedContent {
+		t.Errorf("Expected rendered content to be %q, got %q", expectedContent, string(content))
+	}
+	basePath := filepath.Join(tempDir, "base.yml")
+	if err := os.WriteFile(basePath, []byte(`B[{{ block "body" . }}default{{ end }}]`), 0o600); err != nil {
+		t.Fatalf("Failed to write base template file: %v", err)
+	}
+
+	templatePath, cleanupTemplate = createTempTemplateFile(t, tempDir, `[[ define "body" ]]${{ github.ref }} [[ .Repository ]][[ end ]]`)
+	defer cleanupTemplate()
+
+	opts = RenderOptions{Delims: Delims{"[[", "]]"}, Base: basePath}
+
+	content, err = RenderTemplate(templatePath, TemplateData{Repository: "testrepo"}, opts)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	expectedContent = "B[${{ github.ref }} testrepo]"
+	if string(content) != expectedContent {
+		t.Errorf("Expected rendered content with a base to be %q, got %q", expectedContent, string(content))
+	}
+	workflowBasePath := filepath.Join(tempDir, "workflow-base.yml")
+	workflowBase := `name: [[ .Repository ]]
+if: ${{ github.event_name == 'push' }}
+[[ block "steps" . ]]- run: make[[ end ]]`
+
+	if err := os.WriteFile(workflowBasePath, []byte(workflowBase), 0o600); err != nil {
+		t.Fatalf("Failed to write base template file: %v", err)
+	}
+
+	templatePath, cleanupTemplate = createTempTemplateFile(t, tempDir, `[[ define "steps" ]]- run: make ${{ matrix.target }}[[ end ]]`)
+	defer cleanupTemplate()
+
+	opts = RenderOptions{Delims: Delims{"[[", "]]"}, Base: workflowBasePath, BaseDelims: Delims{"[[", "]]"}}
+
+	content, err = RenderTemplate(templatePath, TemplateData{Repository: "testrepo"}, opts)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	expectedContent = "name: testrepo\nif: ${{ github.event_name == 'push' }}\n- run: make ${{ matrix.target }}"
+	if string(content) != expectedContent {
+		t.Errorf("Expected rendered content with a base using delims to be %q, got %q", expectedContent, string(content))
+	}
+}
+
+func TestRenderTemplate_Strict(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	templatePath, cleanupTemplate := createTempTemplateFile(t, tempDir, `{{.Vars.channel}}`)
+	defer cleanupTemplate()
+
+	content, err := RenderTemplate(templatePath, TemplateData{}, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	if string(content) != "<no value>" {
+		t.Errorf("Expected rendered content to be <no value>, got %s", string(content))
+	}
+
+	_, err = RenderTemplate(templatePath, TemplateData{}, RenderOptions{Strict: true})
+	if err == nil || !strings.Contains(err.Error(), `map has no entry for key "channel"`) {
+		t.Errorf("Expected missing key error, got %v", err)
+	}
+
+	templatePath, cleanupTemplate = createTempTemplateFile(t, tempDir, `{{if false}}{{.Reponame}}{{end}}`)
+	defer cleanupTemplate()
+
+	_, err = RenderTemplate(templatePath, TemplateData{}, RenderOptions{Strict: true})
+	if err == nil || !strings.Contains(err.Error(), "TemplateData has no field Reponame") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
+
 func TestGenerateFileFromTemplate_ParseError(t *testing.T) {
 	// invalid template content
 	invalidTemplateContent := `{{.user} {{.repo}}`
@@ -87,8 +347,8 @@ func TestGenerateFileFromTemplate_ParseError(t *testing.T) {
 	user := "testuser"
 	repo := "testrepo"
 
-	// err = GenerateFileFromTemplate(templatePath, outputPath, user, repo)
-	if err = GenerateFileFromTemplate(templatePath, outputPath, user, repo); err == nil {
+	// err = GenerateFileFromTemplate(templatePath, outputPath, TemplateData{Username: user, Repository: repo}, defaultFileMode, RenderOptions{})
+	if err = GenerateFileFromTemplate(templatePath, outputPath, TemplateData{Username: user, Repository: repo}, defaultFileMode, RenderOptions{}); err == nil {
 		t.Fatalf("Expected parse error, got %v", err)
 	}
 }
@@ -111,7 +371,7 @@ func TestGenerateFileFromTemplate_ExecuteError(t *testing.T) {
 	outputPath := filepath.Join(outputDir, "output.txt")
 
 	// Missing username and repository fields should cause execute error
-	if err = GenerateFileFromTemplate(templatePath, outputPath, "", ""); err == nil {
+	if err = GenerateFileFromTemplate(templatePath, outputPath, TemplateData{}, defaultFileMode, RenderOptions{}); err == nil {
 		t.Fatalf("Expected execute error, got %v", err)
 	}
 }
@@ -141,11 +401,222 @@ func TestGenerateFileFromTemplate_WriteError(t *testing.T) {
 	user := "testuser"
 	repo := "testrepo"
 
-	if err := GenerateFileFromTemplate(templatePath, outputPath, user, repo); err == nil {
+	if err := GenerateFileFromTemplate(templatePath, outputPath, TemplateData{Username: user, Repository: repo}, defaultFileMode, RenderOptions{}); err == nil {
 		t.Fatalf("Expected write permission error, got %v", err)
 	}
 }
 
+func TestGenerateFileFromTemplate_Mode(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	templatePath, cleanupTemplate := createTempTemplateFile(t, tempDir, `#!/bin/sh`)
+	defer cleanupTemplate()
+
+	outputPath := filepath.Join(tempDir, "hook.sh")
+	if err := os.WriteFile(outputPath, []byte("old"), 0o600); err != nil {
+		t.Fatalf("Failed to write existing output file: %v", err)
+	}
+
+	if err := GenerateFileFromTemplate(templatePath, outputPath, TemplateData{}, 0o755, RenderOptions{}); err != nil {
+		t.Fatalf("Failed to generate file from template: %v", err)
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to stat output file: %v", err)
+	}
+
+	if info.Mode().Perm() != 0o755 {
+		t.Errorf("Expected output file mode to be %o, got %o", 0o755, info.Mode().Perm())
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	templateContent := "updates:\n  - schedule: {{ not a template }}\n"
+	templatePath, cleanupTemplate := createTempTemplateFile(t, tempDir, templateContent)
+
+	defer cleanupTemplate()
+
+	outputPath := filepath.Join(tempDir, "dependabot.yml")
+	if err := CopyFile(templatePath, outputPath, 0o640); err != nil {
+		t.Fatalf("Failed to copy file: %v", err)
+	}
+
+	outputContent, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+
+	if string(outputContent) != templateContent {
+		t.Errorf("Expected output content to be %q, got %q", templateContent, string(outputContent))
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to stat output file: %v", err)
+	}
+
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("Expected output file mode to be %o, got %o", 0o640, info.Mode().Perm())
+	}
+}
+
+func TestIsVerbatim(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	textPath := filepath.Join(tempDir, "issue.md")
+	if err := os.WriteFile(textPath, []byte("{{.Repository}}"), 0o600); err != nil {
+		t.Fatalf("Failed to write template file: %v", err)
+	}
+
+	binaryPath := filepath.Join(tempDir, "logo.png")
+	if err := os.WriteFile(binaryPath, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0o600); err != nil {
+		t.Fatalf("Failed to write template file: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		config       TemplateConfig
+		templatePath string
+		expected     bool
+	}{
+		{"text", TemplateConfig{}, textPath, false},
+		{"binary", TemplateConfig{}, binaryPath, true},
+		{"copy", TemplateConfig{Copy: true}, textPath, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := IsVerbatim(tc.config, tc.templatePath)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+
+	if _, err := IsVerbatim(TemplateConfig{}, filepath.Join(tempDir, "missing.md")); err == nil {
+		t.Errorf("Expected error for missing template file, got nil")
+	}
+}
+
+func TestGetFileMode(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	existingPath := filepath.Join(tempDir, "existing.sh")
+	if err := os.WriteFile(existingPath, []byte(""), 0o600); err != nil {
+		t.Fatalf("Failed to write existing output file: %v", err)
+	}
+
+	if err := os.Chmod(existingPath, 0o750); err != nil {
+		t.Fatalf("Failed to change permission: %v", err)
+	}
+
+	missingPath := filepath.Join(tempDir, "missing.sh")
+
+	testCases := []struct {
+		name       string
+		config     TemplateConfig
+		outputPath string
+		expected   os.FileMode
+	}{
+		{"default", TemplateConfig{}, missingPath, defaultFileMode},
+		{"configured", TemplateConfig{Mode: 0o755}, existingPath, 0o755},
+		{"preserved", TemplateConfig{Mode: 0o755, PreserveMode: true}, existingPath, 0o750},
+		{"preserve without existing file", TemplateConfig{Mode: 0o700, PreserveMode: true}, missingPath, 0o700},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := GetFileMode(tc.config, tc.outputPath, defaultFileMode)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != tc.expected {
+				t.Errorf("expected mode %o, got %o", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestListTemplateFiles(t *testing.T) {
+	tempDir, cleanup := createTempConfigFile2(t)
+	defer cleanup()
+
+	templateDir := filepath.Join(tempDir, "ISSUE_TEMPLATE")
+	if err := os.MkdirAll(filepath.Join(templateDir, "nested"), 0o755); err != nil {
+		t.Fatalf("Failed to create template directory: %v", err)
+	}
+
+	for _, name := range []string{"bug.yml", "config.yml", filepath.Join("nested", "feature.yml")} {
+		if err := os.WriteFile(filepath.Join(templateDir, name), []byte(""), 0o600); err != nil {
+			t.Fatalf("Failed to write template file: %v", err)
+		}
+	}
+
+	files, err := ListTemplateFiles(templateDir, ".github/ISSUE_TEMPLATE")
+	if err != nil {
+		t.Fatalf("Failed to list template files: %v", err)
+	}
+
+	expected := []TemplateFile{
+		{filepath.Join(templateDir, "bug.yml"), ".github/ISSUE_TEMPLATE/bug.yml"},
+		{filepath.Join(templateDir, "config.yml"), ".github/ISSUE_TEMPLATE/config.yml"},
+		{filepath.Join(templateDir, "nested", "feature.yml"), ".github/ISSUE_TEMPLATE/nested/feature.yml"},
+	}
+
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %d files, got %v", len(expected), files)
+	}
+
+	for i, file := range files {
+		if file != expected[i] {
+			t.Errorf("Expected file %v, got %v", expected[i], file)
+		}
+	}
+}
+
+func TestListTemplateFiles_SingleFile(t *testing.T) {
+	files, err := ListTemplateFiles("template.tpl", "output.txt")
+	if err != nil {
+		t.Fatalf("Failed to list template files: %v", err)
+	}
+
+	if len(files) != 1 || files[0] != (TemplateFile{"template.tpl", "output.txt"}) {
+		t.Errorf("Expected single template file, got %v", files)
+	}
+}
+
+func TestMergeVars(t *testing.T) {
+	merged := MergeVars(
+		map[string]string{"email": "global@example.com", "channel": "#general"},
+		map[string]string{"email": "template@example.com"},
+		nil,
+		map[string]string{"channel": "#team"},
+	)
+
+	expected := map[string]string{"email": "template@example.com", "channel": "#team"}
+	if len(merged) != len(expected) {
+		t.Fatalf("Expected merged vars %v, got %v", expected, merged)
+	}
+
+	for key, value := range expected {
+		if merged[key] != value {
+			t.Errorf("Expected %s to be %s, got %s", key, value, merged[key])
+		}
+	}
+}
+
 func TestGetTemplatePath(t *testing.T) {
 	templateName := "template"
 	expected := "template.tpl"